@@ -0,0 +1,133 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// AckError is returned by synchronous publishing if the context expired
+// before all subscriptions acknowledged the event by calling Event.Done.
+type AckError struct {
+	// Topic the event was published to.
+	Topic string
+	// Subscriptions that did not acknowledge the event.
+	Subscriptions []*Subscription
+	// Err is the context error.
+	Err error
+}
+
+func (e *AckError) Error() string {
+	ids := make([]string, 0, len(e.Subscriptions))
+	for _, sub := range e.Subscriptions {
+		ids = append(ids, fmt.Sprintf("%d (%s)", sub.ID(), sub.Topic()))
+	}
+
+	return fmt.Sprintf(
+		"event on topic %q not acknowledged by subscriptions %s: %v",
+		e.Topic, strings.Join(ids, ", "), e.Err,
+	)
+}
+
+// Unwrap returns the context error.
+func (e *AckError) Unwrap() error {
+	return e.Err
+}
+
+// ackTracker keeps track of subscriptions that did not acknowledge an event yet.
+type ackTracker struct {
+	mu       sync.Mutex
+	pending  map[uint64]*Subscription
+	failures []*SubscriberError
+	done     chan struct{}
+}
+
+func newAckTracker(subs subscriptionSlice) *ackTracker {
+	a := &ackTracker{ //nolint:exhaustivestruct
+		pending: make(map[uint64]*Subscription, len(subs)),
+		done:    make(chan struct{}),
+	}
+
+	for _, sub := range subs {
+		a.pending[sub.id] = sub
+	}
+
+	if len(a.pending) == 0 {
+		close(a.done)
+	}
+
+	return a
+}
+
+// ack marks the event as acknowledged by a subscription, optionally reporting an error.
+// Acknowledging twice is a no-op.
+func (a *ackTracker) ack(subscriptionID uint64, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	sub, ok := a.pending[subscriptionID]
+	if !ok {
+		return
+	}
+
+	delete(a.pending, subscriptionID)
+
+	if err != nil {
+		a.failures = append(a.failures, &SubscriberError{
+			Subscription: sub,
+			Err:          err,
+		})
+	}
+
+	if len(a.pending) == 0 {
+		close(a.done)
+	}
+}
+
+// wait blocks until all subscriptions acknowledged the event or the context expires.
+func (a *ackTracker) wait(ctx context.Context, topic string) error {
+	select {
+	case <-a.done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// The event may have been acknowledged while the context expired.
+	if len(a.pending) == 0 {
+		return nil
+	}
+
+	subs := make(subscriptionSlice, 0, len(a.pending))
+	for _, sub := range a.pending {
+		subs = append(subs, sub)
+	}
+
+	sort.Slice(subs, func(i, j int) bool {
+		return subs[i].id < subs[j].id
+	})
+
+	return &AckError{
+		Topic:         topic,
+		Subscriptions: subs,
+		Err:           ctx.Err(),
+	}
+}
+
+// errors returns the errors reported by subscriptions ordered by subscription id.
+func (a *ackTracker) errors() []*SubscriberError {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	failures := append([]*SubscriberError{}, a.failures...)
+
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].Subscription.id < failures[j].Subscription.id
+	})
+
+	return failures
+}
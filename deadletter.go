@@ -0,0 +1,36 @@
+package eventbus
+
+// DeadLetter is published to the dead-letter topic for events that failed or matched no subscription.
+type DeadLetter struct {
+	// Event that failed.
+	Event Event
+	// Reason is the error the event failed with, ErrNoSubscribers if no subscription received it.
+	Reason error
+	// Subscription the event failed for, nil if no subscription received it.
+	Subscription *Subscription
+	// Attempts is the number of deliveries made to the subscription, including retries of the handler.
+	Attempts int
+}
+
+// deadLetter publishes a failed event to the dead-letter topic if configured.
+// The subscription is nil for events that matched no subscription.
+func (eb *EventBus) deadLetter(sub *Subscription, evt Event, reason error) {
+	// Events on the dead-letter topic are not routed again.
+	if eb.deadLetterTopic == "" || evt.Topic == eb.deadLetterTopic {
+		return
+	}
+
+	attempts := 0
+	if sub != nil {
+		attempts = evt.Attempt() + evt.retries
+	}
+
+	eb.stats.incDeadLetteredCountByTopic(evt.Topic)
+
+	_ = eb.PublishAsync(eb.deadLetterTopic, DeadLetter{
+		Event:        evt.detached(),
+		Reason:       reason,
+		Subscription: sub,
+		Attempts:     attempts,
+	})
+}
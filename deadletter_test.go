@@ -0,0 +1,116 @@
+package eventbus_test
+
+import (
+	eb "github.com/dtomasi/go-event-bus/v3"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestEventBus_DeadLetterHandlerError(t *testing.T) {
+	ebi := eb.NewEventBus(eb.WithDeadLetterTopic("dead"))
+
+	dead, err := ebi.Subscribe("dead", eb.WithBufferSize(1))
+	assert.NoError(t, err)
+
+	sub, err := ebi.SubscribeHandler("orders", func(topic string, data interface{}) error {
+		return errTest
+	})
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, ebi.PublishE("orders", "order"), errTest)
+
+	letter, ok := (<-dead.Channel()).Data.(eb.DeadLetter)
+	assert.True(t, ok)
+	assert.Equal(t, "orders", letter.Event.Topic)
+	assert.Equal(t, "order", letter.Event.Data)
+	assert.ErrorIs(t, letter.Reason, errTest)
+	assert.Equal(t, sub, letter.Subscription)
+	assert.Equal(t, 1, letter.Attempts)
+	assert.Equal(t, 1, ebi.Stats().GetDeadLetteredCountByTopic("orders"))
+}
+
+func TestEventBus_DeadLetterPanic(t *testing.T) {
+	ebi := eb.NewEventBus(
+		eb.WithDeadLetterTopic("dead"),
+		eb.WithPanicHandler(func(sub *eb.Subscription, evt eb.Event, err *eb.PanicError) {}),
+	)
+
+	dead, err := ebi.Subscribe("dead", eb.WithBufferSize(1))
+	assert.NoError(t, err)
+
+	_, err = ebi.SubscribeCallback("orders", func(topic string, data interface{}) {
+		panic("boom")
+	})
+	assert.NoError(t, err)
+
+	_, err = ebi.Publish("orders", "order")
+	assert.NoError(t, err)
+
+	letter, ok := (<-dead.Channel()).Data.(eb.DeadLetter)
+	assert.True(t, ok)
+
+	var panicErr *eb.PanicError
+
+	assert.ErrorAs(t, letter.Reason, &panicErr)
+	assert.Equal(t, "boom", panicErr.Value)
+}
+
+func TestEventBus_DeadLetterNoSubscribers(t *testing.T) {
+	ebi := eb.NewEventBus(eb.WithDeadLetterTopic("dead"))
+
+	dead, err := ebi.Subscribe("dead", eb.WithBufferSize(1))
+	assert.NoError(t, err)
+
+	assert.NoError(t, ebi.PublishAsync("orders", "order"))
+
+	letter, ok := (<-dead.Channel()).Data.(eb.DeadLetter)
+	assert.True(t, ok)
+	assert.Equal(t, "order", letter.Event.Data)
+	assert.ErrorIs(t, letter.Reason, eb.ErrNoSubscribers)
+	assert.Nil(t, letter.Subscription)
+	assert.Equal(t, 0, letter.Attempts)
+
+	// Retained events are kept for subscriptions created later on
+	assert.NoError(t, ebi.PublishAsync("app:ready", true, eb.WithRetain()))
+	assert.Equal(t, 0, ebi.Stats().GetDeadLetteredCountByTopic("app:ready"))
+}
+
+func TestEventBus_DeadLetterAckTimeout(t *testing.T) {
+	ebi := eb.NewEventBus(eb.WithDeadLetterTopic("dead"))
+
+	dead, err := ebi.Subscribe("dead", eb.WithBufferSize(1))
+	assert.NoError(t, err)
+
+	sub, err := ebi.Subscribe("orders", eb.WithAckTimeout(5*time.Millisecond, 2))
+	assert.NoError(t, err)
+
+	go func() {
+		for range sub.Channel() {
+		}
+	}()
+
+	assert.ErrorIs(t, ebi.PublishE("orders", "order"), eb.ErrAckTimeout)
+
+	letter, ok := (<-dead.Channel()).Data.(eb.DeadLetter)
+	assert.True(t, ok)
+	assert.ErrorIs(t, letter.Reason, eb.ErrAckTimeout)
+	assert.Equal(t, 2, letter.Attempts)
+}
+
+func TestEventBus_DeadLetterLoop(t *testing.T) {
+	ebi := eb.NewEventBus(eb.WithDeadLetterTopic("dead"))
+
+	// Failures on the dead-letter topic are not routed again
+	_, err := ebi.SubscribeHandler("#", func(topic string, data interface{}) error {
+		return errTest
+	})
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, ebi.PublishE("orders", "order"), errTest)
+
+	assert.NoError(t, ebi.Close())
+
+	assert.Equal(t, 1, ebi.Stats().GetDeadLetteredCountByTopic("orders"))
+	assert.Equal(t, 0, ebi.Stats().GetDeadLetteredCountByTopic("dead"))
+}
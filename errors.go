@@ -0,0 +1,95 @@
+package eventbus
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrClosed is returned when publishing to an EventBus that has been closed.
+	ErrClosed = errors.New("event bus is closed")
+	// ErrNoResponders is returned by Request if no subscriber is listening on the topic.
+	ErrNoResponders = errors.New("no responders for request")
+	// ErrNoReplyTopic is returned by Event.Respond if the event was not published using Request.
+	ErrNoReplyTopic = errors.New("event has no reply topic")
+	// ErrInvalidPattern is returned when subscribing with a malformed topic pattern
+	// or publishing to a topic containing wildcard segments.
+	ErrInvalidPattern = errors.New("invalid topic pattern")
+	// ErrGroupStrategyMismatch is returned when joining a consumer group using a different strategy.
+	ErrGroupStrategyMismatch = errors.New("consumer group strategy mismatch")
+	// ErrOverflow is reported for subscriptions using OverflowFail if their channel is full.
+	ErrOverflow = errors.New("subscription channel is full")
+	// ErrAckTimeout is reported for events that were not acknowledged in time by a subscription using WithAckTimeout.
+	ErrAckTimeout = errors.New("event was not acknowledged in time")
+	// ErrNacked is reported for events rejected using Event.Nack.
+	ErrNacked = errors.New("event was rejected")
+	// ErrNoSubscribers is the reason of dead letters for events no subscription received.
+	ErrNoSubscribers = errors.New("no subscribers for event")
+	// ErrInvalidTraceParent is returned when parsing a malformed W3C traceparent.
+	ErrInvalidTraceParent = errors.New("invalid traceparent")
+	// ErrNoEventLog is returned by ReplayLog if the EventBus has no EventLog.
+	ErrNoEventLog = errors.New("event bus has no event log")
+)
+
+// SubscriberError is an error reported by a single subscription.
+type SubscriberError struct {
+	// Subscription that reported the error.
+	Subscription *Subscription
+	// Err is the reported error.
+	Err error
+}
+
+func (e *SubscriberError) Error() string {
+	return fmt.Sprintf("subscription %d (%s): %v", e.Subscription.ID(), e.Subscription.Topic(), e.Err)
+}
+
+// Unwrap returns the reported error.
+func (e *SubscriberError) Unwrap() error {
+	return e.Err
+}
+
+// PublishError joins all errors that occurred while publishing an event.
+type PublishError struct {
+	// Topic the event was published to.
+	Topic string
+	// Errors holds a *SubscriberError for every failed subscription
+	// and an *AckError if not all subscriptions acknowledged the event.
+	Errors []error
+}
+
+func (e *PublishError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+
+	return fmt.Sprintf("publishing to topic %q failed: %s", e.Topic, strings.Join(msgs, "; "))
+}
+
+// Unwrap returns all joined errors.
+func (e *PublishError) Unwrap() []error {
+	return e.Errors
+}
+
+// Is reports whether any of the joined errors matches target.
+func (e *PublishError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first joined error that matches target.
+func (e *PublishError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,131 @@
+package eventbus
+
+import (
+	"context"
+	"time"
+)
+
+// Event holds topic name and data.
+type Event struct {
+	Data  interface{}
+	Topic string
+
+	// ID uniquely identifies the event, see WithEventID.
+	ID string
+	// Timestamp is the time the event was published.
+	Timestamp time.Time
+	// Source identifies the publisher of the event, see WithSource.
+	Source string
+	// Headers hold additional metadata, see WithHeader. They are shared by all subscribers
+	// receiving the event and must not be modified after publishing.
+	Headers map[string]string
+
+	ctx      context.Context //nolint:containedctx
+	acks     *ackTracker
+	sub      *Subscription
+	bus      *EventBus
+	replyTo  string
+	delivery *delivery
+	attempt  int
+	retries  int
+
+	propagation *propagation
+}
+
+// Done acknowledges the event if it was published synchronously.
+func (e *Event) Done() {
+	e.Fail(nil)
+}
+
+// Fail acknowledges the event reporting an error if it was published synchronously.
+// The error is returned to publishers using PublishE and the event is sent to the dead-letter topic.
+// Fail with a nil error is the same as Done.
+// For subscriptions using WithAckTimeout a failed event is redelivered while attempts are left.
+func (e *Event) Fail(err error) {
+	if e.delivery != nil {
+		e.delivery.settle(e.attempt, e.retries, err, err != nil)
+
+		return
+	}
+
+	if e.sub == nil {
+		return
+	}
+
+	if e.acks != nil {
+		e.acks.ack(e.sub.id, err)
+	}
+
+	if err != nil {
+		e.sub.bus.deadLetter(e.sub, *e, err)
+	}
+}
+
+// Header returns the value of a header or an empty string.
+func (e *Event) Header(key string) string {
+	return e.Headers[key]
+}
+
+// Nack rejects the event. For subscriptions using WithAckTimeout the event is redelivered
+// if requeue is true and attempts are left. Otherwise the event fails with ErrNacked.
+func (e *Event) Nack(requeue bool) {
+	if e.delivery != nil {
+		e.delivery.settle(e.attempt, e.retries, ErrNacked, requeue)
+
+		return
+	}
+
+	e.Fail(ErrNacked)
+}
+
+// Attempt returns the number of the delivery attempt, starting with 1.
+// It is only greater than 1 for events redelivered to subscriptions using WithAckTimeout.
+func (e *Event) Attempt() int {
+	if e.attempt == 0 {
+		return 1
+	}
+
+	return e.attempt
+}
+
+// Context returns the context the event was published with.
+func (e *Event) Context() context.Context {
+	if e.ctx == nil {
+		return context.Background()
+	}
+
+	return e.ctx
+}
+
+// ReplyTo returns the unique inbox replies to the event are routed to.
+// It is empty if the event was not published using Request.
+func (e *Event) ReplyTo() string {
+	return e.replyTo
+}
+
+// Respond sends a reply to an event published using Request.
+// Replies are handed to the requester directly and are not published to any topic.
+// It returns ErrNoReplyTopic if the event does not expect a reply and ErrClosed if the EventBus has been closed.
+func (e *Event) Respond(data interface{}) error {
+	if e.replyTo == "" || e.bus == nil {
+		return ErrNoReplyTopic
+	}
+
+	return e.bus.reply(e.replyTo, data)
+}
+
+// detached returns a copy of the event that can be delivered again later on.
+// Acknowledgement, context and reply information only apply to the original publish and are removed.
+func (e Event) detached() Event {
+	e.ctx = nil
+	e.acks = nil
+	e.sub = nil
+	e.bus = nil
+	e.replyTo = ""
+	e.delivery = nil
+	e.attempt = 0
+	e.retries = 0
+	e.propagation = nil
+
+	return e
+}
@@ -1,26 +1,12 @@
 package eventbus
 
 import (
+	"context"
+	"errors"
 	"sync"
+	"sync/atomic"
 )
 
-// Event holds topic name and data.
-type Event struct {
-	Data  interface{}
-	Topic string
-	wg    *sync.WaitGroup
-}
-
-// Done calls Done on sync.WaitGroup if set.
-func (e *Event) Done() {
-	if e.wg != nil {
-		e.wg.Done()
-	}
-}
-
-// CallbackFunc Defines a CallbackFunc.
-type CallbackFunc func(topic string, data interface{})
-
 // EventChannel is a channel which can accept an Event.
 type EventChannel chan Event
 
@@ -29,176 +15,688 @@ func NewEventChannel() EventChannel {
 	return make(EventChannel)
 }
 
-// dataChannelSlice is a slice of DataChannels.
-type eventChannelSlice []EventChannel
+// subscriptionSlice is a slice of Subscriptions.
+type subscriptionSlice []*Subscription
 
 // EventBus stores the information about subscribers interested for a particular topic.
 type EventBus struct {
 	mu          sync.RWMutex
-	subscribers map[string]eventChannelSlice
+	subscribers map[string]subscriptionSlice
 	stats       *Stats
+	lastID      uint64
+	lastInboxID uint64
+	pending     *pendingTracker
+	closed      bool
+
+	panicHandler PanicHandler
+	matcher      TopicMatcher
+	index        topicIndex
+	cache        *matchCache
+	groups       map[string]*consumerGroup
+	log          EventLog
+	tracer       Tracer
+
+	publishMiddleware []PublishMiddleware
+	handlerMiddleware []HandlerMiddleware
+
+	deadLetterTopic string
+
+	logMu     sync.RWMutex
+	logClosed bool
+
+	retainedMu sync.Mutex
+	retained   map[string]Event
+
+	replayConfigs []replayConfig
+	replayMu      sync.Mutex
+	replays       map[string]*replayBuffer
+	replaySeq     uint64
+
+	inboxMu sync.Mutex
+	inboxes map[string]*eventQueue
+	done    chan struct{}
 }
 
 // NewEventBus returns a new EventBus instance.
-func NewEventBus() *EventBus {
-	return &EventBus{ //nolint:exhaustivestruct
-		subscribers: map[string]eventChannelSlice{},
-		stats:       newStats(),
+func NewEventBus(opts ...Option) *EventBus {
+	eb := &EventBus{ //nolint:exhaustivestruct
+		subscribers:  map[string]subscriptionSlice{},
+		stats:        newStats(),
+		pending:      newPendingTracker(),
+		panicHandler: logPanic,
+		matcher:      NewSegmentMatcher(DefaultSeparator),
+		cache:        newMatchCache(),
+		groups:       map[string]*consumerGroup{},
+		retained:     map[string]Event{},
+		replays:      map[string]*replayBuffer{},
+		inboxes:      map[string]*eventQueue{},
+		done:         make(chan struct{}),
 	}
+
+	for _, opt := range opts {
+		opt(eb)
+	}
+
+	eb.index = newTopicIndex(eb.matcher)
+
+	return eb
 }
 
-// getSubscribingChannels returns all subscribing channels including wildcard matches.
-func (eb *EventBus) getSubscribingChannels(topic string) eventChannelSlice {
-	subChannels := eventChannelSlice{}
+// getSubscriptions returns all subscriptions including wildcard matches.
+func (eb *EventBus) getSubscriptions(topic string) subscriptionSlice {
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
 
-	for topicName := range eb.subscribers {
-		if topicName == topic || matchWildcard(topicName, topic) {
-			subChannels = append(subChannels, eb.subscribers[topicName]...)
-		}
+	return eb.matchSubscriptions(topic)
+}
+
+// matchSubscriptions returns all subscriptions including wildcard matches ordered by priority.
+// The caller must hold the lock. The returned slice is shared and must not be modified.
+func (eb *EventBus) matchSubscriptions(topic string) subscriptionSlice {
+	if subs, ok := eb.cache.get(topic); ok {
+		return subs
+	}
+
+	subs := subscriptionSlice{}
+
+	for _, pattern := range eb.index.match(topic) {
+		subs = append(subs, eb.subscribers[pattern]...)
 	}
 
-	return subChannels
+	sortByPriority(subs)
+
+	eb.cache.set(topic, subs)
+
+	return subs
 }
 
-// doPublish is publishing events to channels internally.
-func (eb *EventBus) doPublish(channels eventChannelSlice, evt Event) {
+// prepareSubscriptions returns all subscriptions for the event topic accepting the event
+// and registers a pending event. matched is false if the topic matched no subscription at all.
+// Retaining and recording the event for replay happens under the same lock, so new subscriptions
+// either receive it as retained or replayed event or as regular event.
+// The event is appended to the EventLog holding the log lock instead of the lock of the EventBus,
+// so slow appends do not block subscribing. Close waits for the log lock, so rejected events are not logged.
+// It returns ErrClosed if the EventBus has been closed and an error wrapping ErrInvalidPattern
+// if the topic contains wildcards.
+func (eb *EventBus) prepareSubscriptions(evt Event, o *publishOptions) (subscriptionSlice, bool, error) {
+	if err := eb.validateTopic(evt.Topic); err != nil {
+		return nil, false, err
+	}
+
+	eb.logMu.RLock()
+	defer eb.logMu.RUnlock()
+
+	if err := eb.logEvent(evt, o); err != nil {
+		return nil, false, err
+	}
+
 	eb.mu.RLock()
 	defer eb.mu.RUnlock()
 
-	go func(channels eventChannelSlice, evt Event) {
-		for _, ch := range channels {
-			ch <- evt
-		}
-	}(channels, evt)
+	if eb.closed {
+		return nil, false, ErrClosed
+	}
+
+	if o.retain {
+		eb.retain(evt)
+	}
+
+	eb.record(evt)
+
+	// Filters are applied before selecting group members, so a member accepting the event is selected.
+	matched := eb.matchSubscriptions(evt.Topic)
+	subs := eb.selectGroupMembers(eb.filterSubscriptions(matched, evt))
+
+	eb.pending.add()
+
+	return subs, len(matched) > 0, nil
 }
 
-// Code from https://github.com/minio/minio/blob/master/pkg/wildcard/match.go
-func matchWildcard(pattern, name string) bool {
-	if pattern == "" {
-		return name == pattern
+// validateTopic returns an error wrapping ErrInvalidPattern if a published topic contains wildcard segments,
+// which the topic index of the SegmentMatcher would match as wildcards.
+func (eb *EventBus) validateTopic(topic string) error {
+	if matcher, ok := eb.matcher.(*SegmentMatcher); ok {
+		return matcher.validateTopic(topic)
 	}
 
-	if pattern == "*" {
-		return true
+	return nil
+}
+
+// unrouted sends the event to the dead-letter topic if it matched no subscription.
+// Retained events are expected to be received by subscriptions created later on.
+// Events rejected by the filters of all subscriptions are not dead-lettered.
+func (eb *EventBus) unrouted(matched bool, evt Event, o *publishOptions) {
+	if !matched && !o.retain {
+		eb.deadLetter(nil, evt, ErrNoSubscribers)
 	}
-	// Does only wildcard '*' match.
-	return deepMatchRune([]rune(name), []rune(pattern), true)
 }
 
-// Code from https://github.com/minio/minio/blob/master/pkg/wildcard/match.go
-func deepMatchRune(str, pattern []rune, simple bool) bool { //nolint:unparam
-	for len(pattern) > 0 {
-		switch pattern[0] {
-		default:
-			if len(str) == 0 || str[0] != pattern[0] {
-				return false
+// doPublish is publishing events to subscriptions internally.
+// The event must be registered as pending by prepareSubscriptions before.
+// Subscriptions using a non-blocking overflow policy are served right away,
+// a *SubscriberError is returned for every subscription that failed with ErrOverflow.
+func (eb *EventBus) doPublish(subs subscriptionSlice, evt Event) []error {
+	var (
+		ordered    subscriptionSlice
+		concurrent subscriptionSlice
+		errs       []error
+	)
+
+	for _, sub := range subs {
+		switch {
+		case sub.overflowPolicy != OverflowBlock:
+			if err := sub.tryDeliver(evt); err != nil {
+				if err = eb.overflow(sub, evt); err != nil {
+					errs = append(errs, err)
+				}
 			}
-		case '*':
-			return deepMatchRune(str, pattern[1:], simple) ||
-				(len(str) > 0 && deepMatchRune(str[1:], pattern, simple))
+		case sub.ordered():
+			ordered = append(ordered, sub)
+		default:
+			concurrent = append(concurrent, sub)
 		}
+	}
+
+	handoffs := len(ordered)
+	if len(concurrent) > 0 {
+		handoffs++
+	}
+
+	if handoffs == 0 {
+		eb.pending.done()
+
+		return errs
+	}
+
+	release := newRelease(handoffs, eb.pending.done)
+
+	for _, sub := range ordered {
+		sub.enqueue(evt, release)
+	}
+
+	if len(concurrent) > 0 {
+		go func(subs subscriptionSlice, evt Event) {
+			defer release()
+
+			for _, sub := range subs {
+				sub.deliver(evt)
+			}
+		}(concurrent, evt)
+	}
+
+	return errs
+}
 
-		str = str[1:]
+// PublishAsync data to a topic asynchronously.
+// It returns ErrClosed if the EventBus has been closed and a *PublishError
+// if subscriptions using OverflowFail could not receive the event.
+func (eb *EventBus) PublishAsync(topic string, data interface{}, opts ...PublishOption) error {
+	return eb.PublishAsyncCtx(context.Background(), topic, data, opts...)
+}
 
-		pattern = pattern[1:]
+// PublishAsyncCtx same as PublishAsync but deliveries are abandoned once the context expires.
+// The context is available to subscribers through Event.Context.
+func (eb *EventBus) PublishAsyncCtx(
+	ctx context.Context,
+	topic string,
+	data interface{},
+	opts ...PublishOption,
+) error {
+	_, err := eb.publishAsync(Event{ //nolint:exhaustivestruct
+		Data:  data,
+		Topic: topic,
+		ctx:   ctx,
+	}, newPublishOptions(opts))
+
+	return err
+}
+
+// publishAsync publishes an event through the publish middleware without waiting for subscriptions.
+// It returns the number of subscriptions the event is delivered to.
+func (eb *EventBus) publishAsync(evt Event, o *publishOptions) (int, error) {
+	if err := evt.Context().Err(); err != nil {
+		return 0, err
 	}
 
-	return len(str) == 0 && len(pattern) == 0
+	evt, span := eb.trace(o.stamp(evt))
+	count := 0
+
+	err := eb.intercept(evt, func(evt Event) error {
+		var err error
+		count, err = eb.routeAsync(evt, o)
+
+		return err
+	})
+
+	endSpan(span, err)
+
+	return count, err
 }
 
-// PublishAsync data to a topic asynchronously
-// This function returns a bool channel which indicates that all subscribers where called.
-func (eb *EventBus) PublishAsync(topic string, data interface{}) {
-	eb.doPublish(
-		eb.getSubscribingChannels(topic),
-		Event{
-			Data:  data,
-			Topic: topic,
-			wg:    nil,
-		})
+// routeAsync hands the event over to all matching subscriptions.
+func (eb *EventBus) routeAsync(evt Event, o *publishOptions) (int, error) {
+	subs, matched, err := eb.prepareSubscriptions(evt, o)
+	if err != nil {
+		return 0, err
+	}
+
+	eb.unrouted(matched, evt, o)
+
+	errs := eb.doPublish(subs, evt)
 
-	eb.stats.incPublishedCountByTopic(topic)
+	eb.stats.incPublishedCountByTopic(evt.Topic)
+
+	if len(errs) > 0 {
+		return len(subs), &PublishError{
+			Topic:  evt.Topic,
+			Errors: errs,
+		}
+	}
+
+	return len(subs), nil
 }
 
 // PublishAsyncOnce same as PublishAsync but makes sure that topic is only published once.
-func (eb *EventBus) PublishAsyncOnce(topic string, data interface{}) {
+// Use WithRetain to deliver the event to subscriptions created after it was published.
+func (eb *EventBus) PublishAsyncOnce(topic string, data interface{}, opts ...PublishOption) error {
 	if eb.stats.GetPublishedCountByTopic(topic) > 0 {
-		return
+		return nil
 	}
 
-	eb.PublishAsync(topic, data)
+	return eb.PublishAsync(topic, data, opts...)
 }
 
 // Publish data to a topic and wait for all subscribers to finish
-// This function creates a waitGroup internally. All subscribers must call Done() function on Event.
-func (eb *EventBus) Publish(topic string, data interface{}) interface{} {
-	wg := sync.WaitGroup{}
-	channels := eb.getSubscribingChannels(topic)
-	wg.Add(len(channels))
-	eb.doPublish(
-		channels,
-		Event{
-			Data:  data,
-			Topic: topic,
-			wg:    &wg,
-		})
-	wg.Wait()
+// All subscribers must call Done() function on Event.
+// It returns ErrClosed if the EventBus has been closed and a *PublishError
+// if subscriptions using OverflowFail could not receive the event.
+func (eb *EventBus) Publish(topic string, data interface{}, opts ...PublishOption) (interface{}, error) {
+	if err := eb.PublishCtx(context.Background(), topic, data, opts...); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// PublishCtx same as Publish but stops waiting for subscribers once the context expires.
+// In that case an *AckError is returned, holding the subscriptions that did not call Done() on Event.
+// The context is available to subscribers through Event.Context.
+func (eb *EventBus) PublishCtx(ctx context.Context, topic string, data interface{}, opts ...PublishOption) error {
+	acks, err := eb.publishSync(Event{ //nolint:exhaustivestruct
+		Data:  data,
+		Topic: topic,
+		ctx:   ctx,
+	}, newPublishOptions(opts))
+	// Events dropped by publish middleware are not routed.
+	if err != nil || acks == nil {
+		return err
+	}
+
+	// Only overflow failures are reported, other subscriber errors are reported by PublishE.
+	var errs []error
+
+	for _, failure := range acks.errors() {
+		if errors.Is(failure, ErrOverflow) {
+			errs = append(errs, failure)
+		}
+	}
+
+	if len(errs) > 0 {
+		return &PublishError{
+			Topic:  topic,
+			Errors: errs,
+		}
+	}
+
+	return nil
+}
+
+// PublishE same as Publish but returns the errors reported by subscribers.
+// If any subscriber failed a *PublishError holding a *SubscriberError per failed subscription is returned.
+func (eb *EventBus) PublishE(topic string, data interface{}, opts ...PublishOption) error {
+	return eb.PublishECtx(context.Background(), topic, data, opts...)
+}
+
+// PublishECtx same as PublishE but stops waiting for subscribers once the context expires.
+// In that case the returned *PublishError also holds an *AckError.
+func (eb *EventBus) PublishECtx(ctx context.Context, topic string, data interface{}, opts ...PublishOption) error {
+	acks, err := eb.publishSync(Event{ //nolint:exhaustivestruct
+		Data:  data,
+		Topic: topic,
+		ctx:   ctx,
+	}, newPublishOptions(opts))
+	if acks == nil {
+		return err
+	}
+
+	var errs []error
+	for _, failure := range acks.errors() {
+		errs = append(errs, failure)
+	}
+
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &PublishError{
+		Topic:  topic,
+		Errors: errs,
+	}
+}
+
+// publishSync publishes an event through the publish middleware and waits for all subscriptions
+// to acknowledge it. The returned ackTracker is nil if the event could not be published at all.
+func (eb *EventBus) publishSync(evt Event, o *publishOptions) (*ackTracker, error) {
+	if err := evt.Context().Err(); err != nil {
+		return nil, err
+	}
+
+	evt, span := eb.trace(o.stamp(evt))
+
+	var acks *ackTracker
 
-	eb.stats.incPublishedCountByTopic(topic)
+	err := eb.intercept(evt, func(evt Event) error {
+		var err error
+		acks, err = eb.routeSync(evt, o)
 
-	return data
+		return err
+	})
+
+	endSpan(span, err)
+
+	return acks, err
+}
+
+// routeSync hands the event over to all matching subscriptions and waits for them.
+func (eb *EventBus) routeSync(evt Event, o *publishOptions) (*ackTracker, error) {
+	subs, matched, err := eb.prepareSubscriptions(evt, o)
+	if err != nil {
+		return nil, err
+	}
+
+	eb.unrouted(matched, evt, o)
+
+	if o.sequential {
+		acks, err := eb.dispatchSequential(subs, evt)
+		eb.stats.incPublishedCountByTopic(evt.Topic)
+
+		return acks, err
+	}
+
+	evt.acks = newAckTracker(subs)
+
+	// Overflow failures are recorded by the ackTracker.
+	_ = eb.doPublish(subs, evt)
+
+	eb.stats.incPublishedCountByTopic(evt.Topic)
+
+	return evt.acks, evt.acks.wait(evt.Context(), evt.Topic)
 }
 
 // PublishOnce same as Publish but makes sure only published once on topic.
-func (eb *EventBus) PublishOnce(topic string, data interface{}) interface{} {
+// Use WithRetain to deliver the event to subscriptions created after it was published.
+func (eb *EventBus) PublishOnce(topic string, data interface{}, opts ...PublishOption) (interface{}, error) {
 	if eb.stats.GetPublishedCountByTopic(topic) > 0 {
-		return nil
+		return nil, nil
 	}
 
-	return eb.Publish(topic, data)
+	return eb.Publish(topic, data, opts...)
 }
 
 // Subscribe to a topic passing a EventChannel.
-func (eb *EventBus) Subscribe(topic string) EventChannel {
-	ch := make(EventChannel)
-	eb.SubscribeChannel(topic, ch)
-
-	eb.stats.incSubscriberCountByTopic(topic)
+// The topic may contain wildcards supported by the TopicMatcher of the EventBus.
+// It returns an error wrapping ErrInvalidPattern for malformed patterns and ErrClosed if the EventBus has been closed.
+func (eb *EventBus) Subscribe(topic string, opts ...SubscribeOption) (*Subscription, error) {
+	o := newSubscribeOptions(opts)
 
-	return ch
+	return eb.subscribe(topic, o.newChannel(), true, o)
 }
 
 // SubscribeChannel subscribes to a given Channel.
-func (eb *EventBus) SubscribeChannel(topic string, ch EventChannel) {
+func (eb *EventBus) SubscribeChannel(topic string, ch EventChannel, opts ...SubscribeOption) (*Subscription, error) {
+	return eb.subscribe(topic, ch, false, newSubscribeOptions(opts))
+}
+
+// subscribe registers a new Subscription for the given topic and channel
+// and delivers the retained or replayed events matching the topic to it.
+func (eb *EventBus) subscribe(
+	topic string,
+	ch EventChannel,
+	owned bool,
+	o *subscribeOptions,
+) (*Subscription, error) {
+	if err := eb.matcher.Validate(topic); err != nil {
+		return nil, err
+	}
+
+	sub, initial, err := eb.register(topic, ch, owned, o)
+	if err != nil {
+		return nil, err
+	}
+
+	// Overflow handling may publish, so it happens without holding the lock.
+	for _, evt := range initial {
+		if sub.overflowPolicy != OverflowBlock {
+			_ = eb.overflow(sub, evt)
+
+			continue
+		}
+
+		eb.pending.add()
+		_ = eb.doPublish(subscriptionSlice{sub}, evt)
+	}
+
+	return sub, nil
+}
+
+// register adds a new Subscription to the EventBus. Retained or replayed events are enqueued right away
+// for ordered subscriptions and handed over right away to subscriptions using a non-blocking overflow policy,
+// so they are received before any event published later on. For subscriptions using a non-blocking overflow policy
+// the events that overflowed are returned, for subscriptions using DeliveryConcurrent all events are returned.
+func (eb *EventBus) register(
+	topic string,
+	ch EventChannel,
+	owned bool,
+	o *subscribeOptions,
+) (*Subscription, []Event, error) {
 	eb.mu.Lock()
 	defer eb.mu.Unlock()
 
-	if prev, found := eb.subscribers[topic]; found {
-		eb.subscribers[topic] = append(prev, ch)
-	} else {
-		eb.subscribers[topic] = append([]EventChannel{}, ch)
+	if eb.closed {
+		return nil, nil, ErrClosed
+	}
+
+	// Members joining an existing group do not receive retained or replayed events again.
+	_, joined := eb.groups[o.group]
+
+	if o.group != "" {
+		if err := eb.joinGroup(o.group, o.groupStrategy); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if _, ok := eb.subscribers[topic]; !ok {
+		eb.index.add(topic)
+	}
+
+	sub := newSubscription(eb, atomic.AddUint64(&eb.lastID, 1), topic, ch, owned, o)
+	eb.subscribers[topic] = append(eb.subscribers[topic], sub)
+	eb.cache.reset()
+
+	if sub.ordered() {
+		go sub.dispatch()
+	}
+
+	if o.handler != nil {
+		eb.startHandlers(sub, o)
 	}
 
 	eb.stats.incSubscriberCountByTopic(topic)
+
+	if joined {
+		return sub, nil, nil
+	}
+
+	// Replayed events take the place of retained events, so no event is received twice.
+	var initial []Event
+	if o.replay() {
+		initial = eb.replay(topic, o)
+	} else {
+		initial = eb.matchRetained(topic)
+	}
+
+	initial = eb.filterEvents(sub, initial)
+
+	if sub.ordered() {
+		for _, evt := range initial {
+			eb.pending.add()
+			_ = eb.doPublish(subscriptionSlice{sub}, evt)
+		}
+
+		return sub, nil, nil
+	}
+
+	if sub.overflowPolicy != OverflowBlock {
+		return sub, sub.offerAll(initial), nil
+	}
+
+	return sub, initial, nil
 }
 
-// SubscribeCallback provides a simple wrapper that allows to directly register CallbackFunc instead of channels.
-func (eb *EventBus) SubscribeCallback(topic string, callable CallbackFunc) {
-	ch := NewEventChannel()
-	eb.SubscribeChannel(topic, ch)
+// unsubscribe removes a single Subscription from the EventBus.
+func (eb *EventBus) unsubscribe(sub *Subscription) {
+	eb.removeSubscriptions(sub.topic, func(s *Subscription) bool {
+		return s == sub
+	})
+}
 
-	go func(callable CallbackFunc) {
-		evt := <-ch
-		callable(evt.Topic, evt.Data)
-		evt.Done()
-	}(callable)
+// UnsubscribeChannel removes all subscriptions of the given channel from a topic.
+// The channel itself is not closed.
+func (eb *EventBus) UnsubscribeChannel(topic string, ch EventChannel) {
+	eb.removeSubscriptions(topic, func(s *Subscription) bool {
+		return s.ch == ch
+	})
+}
 
-	eb.stats.incSubscriberCountByTopic(topic)
+// UnsubscribeAll removes all subscriptions from a topic.
+func (eb *EventBus) UnsubscribeAll(topic string) {
+	eb.removeSubscriptions(topic, func(s *Subscription) bool {
+		return true
+	})
+}
+
+// removeSubscriptions removes all subscriptions of a topic that match the given function.
+// Topics without any subscriptions left are removed from the subscribers map.
+func (eb *EventBus) removeSubscriptions(topic string, match func(s *Subscription) bool) {
+	var removed subscriptionSlice
+
+	eb.mu.Lock()
+
+	kept := subscriptionSlice{}
+
+	for _, sub := range eb.subscribers[topic] {
+		if match(sub) {
+			removed = append(removed, sub)
+		} else {
+			kept = append(kept, sub)
+		}
+	}
+
+	if len(removed) > 0 {
+		if len(kept) == 0 {
+			delete(eb.subscribers, topic)
+			eb.index.remove(topic)
+		} else {
+			eb.subscribers[topic] = kept
+		}
+
+		eb.cache.reset()
+	}
+
+	for _, sub := range removed {
+		eb.stats.decSubscriberCountByTopic(topic)
+
+		if sub.group != "" {
+			eb.leaveGroup(sub)
+		}
+	}
+
+	eb.mu.Unlock()
+
+	// Stopping waits for pending deliveries, so it must not happen while holding the lock.
+	for _, sub := range removed {
+		sub.stop()
+	}
+}
+
+// Close closes the EventBus. All subscriptions are removed and their channels are closed,
+// including channels passed to SubscribeChannel. Pending deliveries are abandoned.
+// Subsequent publishing returns ErrClosed. Use Drain before Close to wait for pending deliveries.
+func (eb *EventBus) Close() error {
+	// Appends in progress are completed, later events are not logged anymore.
+	eb.logMu.Lock()
+	eb.logClosed = true
+	eb.logMu.Unlock()
+
+	eb.mu.Lock()
+
+	if eb.closed {
+		eb.mu.Unlock()
+
+		return ErrClosed
+	}
+
+	eb.closed = true
+	close(eb.done)
+	subscribers := eb.subscribers
+	eb.subscribers = map[string]subscriptionSlice{}
+	eb.index = newTopicIndex(eb.matcher)
+	eb.cache.reset()
+
+	for _, subs := range subscribers {
+		for _, sub := range subs {
+			if sub.group != "" {
+				eb.leaveGroup(sub)
+			}
+		}
+	}
+
+	eb.mu.Unlock()
+
+	// Channels may be subscribed to multiple topics, so they are collected to be closed only once.
+	channels := map[EventChannel]struct{}{}
+
+	for topic, subs := range subscribers {
+		for _, sub := range subs {
+			sub.stop()
+			eb.stats.decSubscriberCountByTopic(topic)
+
+			if !sub.owned {
+				channels[sub.ch] = struct{}{}
+			}
+		}
+	}
+
+	for ch := range channels {
+		close(ch)
+	}
+
+	return nil
+}
+
+// Drain waits until all published events have been delivered to their subscribers or the context expires.
+// If the context expires the number of abandoned events is returned along with the context error.
+func (eb *EventBus) Drain(ctx context.Context) (int, error) {
+	return eb.pending.wait(ctx)
 }
 
 // HasSubscribers Check if a topic has subscribers.
 func (eb *EventBus) HasSubscribers(topic string) bool {
-	return len(eb.getSubscribingChannels(topic)) > 0
+	return len(eb.getSubscriptions(topic)) > 0
 }
 
 // Stats returns the stats map.
@@ -1,10 +1,12 @@
 package eventbus_test
 
 import (
+	"context"
 	eb "github.com/dtomasi/go-event-bus/v3"
 	"github.com/stretchr/testify/assert"
 	"sync"
 	"testing"
+	"time"
 )
 
 func TestNewEventBus(t *testing.T) {
@@ -15,7 +17,8 @@ func TestNewEventBus(t *testing.T) {
 
 func TestEventBus_Subscribe(t *testing.T) {
 	ebi := eb.NewEventBus()
-	_ = ebi.Subscribe("foo")
+	_, err := ebi.Subscribe("foo")
+	assert.NoError(t, err)
 
 	assert.True(t, ebi.HasSubscribers("foo"))
 }
@@ -23,7 +26,8 @@ func TestEventBus_Subscribe(t *testing.T) {
 func TestEventBus_SubscribeChannel(t *testing.T) {
 	ebi := eb.NewEventBus()
 	ch := eb.NewEventChannel()
-	ebi.SubscribeChannel("foo", ch)
+	_, err := ebi.SubscribeChannel("foo", ch)
+	assert.NoError(t, err)
 
 	assert.True(t, ebi.HasSubscribers("foo"))
 }
@@ -33,8 +37,12 @@ func TestEventBus_PublishAsync(t *testing.T) {
 
 	ebi := eb.NewEventBus()
 	ch1 := eb.NewEventChannel()
-	ebi.SubscribeChannel(testTopicName, ch1)
-	ch2 := ebi.Subscribe("foo:*")
+	_, err := ebi.SubscribeChannel(testTopicName, ch1)
+	assert.NoError(t, err)
+	sub2, err := ebi.Subscribe("foo:*")
+	assert.NoError(t, err)
+
+	ch2 := sub2.Channel()
 
 	var wg sync.WaitGroup
 
@@ -78,8 +86,12 @@ func TestEventBus_Publish(t *testing.T) {
 
 	ebi := eb.NewEventBus()
 	ch1 := eb.NewEventChannel()
-	ebi.SubscribeChannel(testTopicName, ch1)
-	ch2 := ebi.Subscribe("foo:*")
+	_, err := ebi.SubscribeChannel(testTopicName, ch1)
+	assert.NoError(t, err)
+	sub2, err := ebi.Subscribe("foo:*")
+	assert.NoError(t, err)
+
+	ch2 := sub2.Channel()
 
 	callCounter := eb.NewSafeCounter()
 
@@ -135,7 +147,7 @@ func TestEventBus_SubscribeCallback(t *testing.T) {
 
 	callCounter := eb.NewSafeCounter()
 
-	ebi.SubscribeCallback(testTopicName, func(topic string, data interface{}) {
+	_, err := ebi.SubscribeCallback(testTopicName, func(topic string, data interface{}) {
 		if topic != testTopicName {
 			t.Fail()
 		}
@@ -145,8 +157,9 @@ func TestEventBus_SubscribeCallback(t *testing.T) {
 		}
 		callCounter.Inc()
 	})
+	assert.NoError(t, err)
 
-	ebi.SubscribeCallback("foo:*", func(topic string, data interface{}) {
+	_, err = ebi.SubscribeCallback("foo:*", func(topic string, data interface{}) {
 		if topic != testTopicName {
 			t.Fail()
 		}
@@ -156,6 +169,7 @@ func TestEventBus_SubscribeCallback(t *testing.T) {
 		}
 		callCounter.Inc()
 	})
+	assert.NoError(t, err)
 
 	ebi.Publish(testTopicName, "bar")
 
@@ -169,3 +183,175 @@ func TestEventBus_SubscribeCallback(t *testing.T) {
 	// Count should be still 1
 	assert.Equal(t, 1, ebi.Stats().GetPublishedCountByTopic(testTopicName))
 }
+
+func TestEventBus_SubscribeCallbackPersistent(t *testing.T) {
+	const testTopicName = "foo:bar"
+
+	ebi := eb.NewEventBus()
+
+	callCounter := eb.NewSafeCounter()
+
+	sub, err := ebi.SubscribeCallback(testTopicName, func(topic string, data interface{}) {
+		callCounter.Inc()
+	})
+	assert.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		ebi.Publish(testTopicName, i)
+	}
+
+	assert.Equal(t, 10, callCounter.Value())
+
+	sub.Unsubscribe()
+
+	// Publishing after unsubscribe must not invoke the callback
+	ebi.Publish(testTopicName, "bar")
+
+	assert.Equal(t, 10, callCounter.Value())
+}
+
+func TestEventBus_SubscribeCallbackConcurrency(t *testing.T) {
+	const (
+		testTopicName = "foo:bar"
+		workers       = 4
+	)
+
+	ebi := eb.NewEventBus()
+
+	running := make(chan struct{}, workers)
+	release := make(chan struct{})
+
+	_, err := ebi.SubscribeCallback(testTopicName, func(topic string, data interface{}) {
+		running <- struct{}{}
+		<-release
+	}, eb.WithConcurrency(workers))
+	assert.NoError(t, err)
+
+	for i := 0; i < workers; i++ {
+		ebi.PublishAsync(testTopicName, i)
+	}
+
+	// All workers must be running at the same time
+	for i := 0; i < workers; i++ {
+		<-running
+	}
+
+	close(release)
+}
+
+func TestEventBus_Close(t *testing.T) {
+	const testTopicName = "foo:bar"
+
+	ebi := eb.NewEventBus()
+	sub, err := ebi.Subscribe(testTopicName)
+	assert.NoError(t, err)
+
+	ch := eb.NewEventChannel()
+	_, err = ebi.SubscribeChannel("foo:*", ch)
+	assert.NoError(t, err)
+	_, err = ebi.SubscribeChannel("bar", ch)
+	assert.NoError(t, err)
+
+	assert.NoError(t, ebi.Close())
+	assert.ErrorIs(t, ebi.Close(), eb.ErrClosed)
+
+	_, ok := <-sub.Channel()
+	assert.False(t, ok)
+
+	_, ok = <-ch
+	assert.False(t, ok)
+
+	assert.False(t, ebi.HasSubscribers(testTopicName))
+
+	_, err = ebi.Publish(testTopicName, "bar")
+	assert.ErrorIs(t, err, eb.ErrClosed)
+	assert.ErrorIs(t, ebi.PublishAsync(testTopicName, "bar"), eb.ErrClosed)
+
+	_, err = ebi.Subscribe(testTopicName)
+	assert.ErrorIs(t, err, eb.ErrClosed)
+}
+
+func TestEventBus_Drain(t *testing.T) {
+	const testTopicName = "foo:bar"
+
+	ebi := eb.NewEventBus()
+	sub, err := ebi.Subscribe(testTopicName)
+	assert.NoError(t, err)
+
+	assert.NoError(t, ebi.PublishAsync(testTopicName, "bar"))
+
+	// Nobody reads the channel, so the event is abandoned
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	abandoned, err := ebi.Drain(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, abandoned)
+
+	<-sub.Channel()
+
+	abandoned, err = ebi.Drain(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, abandoned)
+
+	assert.NoError(t, ebi.Close())
+}
+
+func TestEventBus_PublishCtx(t *testing.T) {
+	const testTopicName = "foo:bar"
+
+	type ctxKey struct{}
+
+	ebi := eb.NewEventBus()
+
+	// Acknowledging subscriber
+	_, err := ebi.SubscribeCallback(testTopicName, func(topic string, data interface{}) {})
+	assert.NoError(t, err)
+
+	// Subscriber that never calls Done and checks the context value
+	lazy, err := ebi.Subscribe("foo:*")
+	assert.NoError(t, err)
+
+	go func() {
+		for evt := range lazy.Channel() {
+			assert.Equal(t, "value", evt.Context().Value(ctxKey{}))
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), ctxKey{}, "value"), 20*time.Millisecond)
+	defer cancel()
+
+	err = ebi.PublishCtx(ctx, testTopicName, "bar")
+
+	var ackErr *eb.AckError
+
+	assert.ErrorAs(t, err, &ackErr)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, testTopicName, ackErr.Topic)
+	assert.Len(t, ackErr.Subscriptions, 1)
+	assert.Equal(t, lazy.ID(), ackErr.Subscriptions[0].ID())
+	assert.Equal(t, 1, ebi.Stats().GetPublishedCountByTopic(testTopicName))
+}
+
+func TestEventBus_PublishAsyncCtx(t *testing.T) {
+	const testTopicName = "foo:bar"
+
+	ebi := eb.NewEventBus()
+	_, err := ebi.Subscribe(testTopicName)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	assert.NoError(t, ebi.PublishAsyncCtx(ctx, testTopicName, "bar"))
+
+	// Cancelling the context abandons the delivery to the non reading subscriber
+	cancel()
+
+	abandoned, err := ebi.Drain(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, abandoned)
+
+	// Publishing with a cancelled context fails
+	assert.ErrorIs(t, ebi.PublishAsyncCtx(ctx, testTopicName, "bar"), context.Canceled)
+	assert.ErrorIs(t, ebi.PublishCtx(ctx, testTopicName, "bar"), context.Canceled)
+}
@@ -0,0 +1,56 @@
+package eventbus
+
+import (
+	"fmt"
+)
+
+// EventLog persists published events before they are delivered.
+// See the wal package for a file based implementation.
+type EventLog interface {
+	// Append persists the topic, the metadata and the data of an event.
+	Append(evt Event) error
+	// Replay calls fn for every persisted event in the order they were appended.
+	Replay(fn func(evt Event) error) error
+}
+
+// logEvent appends the event to the EventLog of the EventBus. The caller must hold the log lock.
+// Replayed events are not logged. It returns ErrClosed if the EventBus has been closed.
+func (eb *EventBus) logEvent(evt Event, o *publishOptions) error {
+	if eb.log == nil || o.replayed {
+		return nil
+	}
+
+	if eb.logClosed {
+		return ErrClosed
+	}
+
+	if err := eb.log.Append(evt); err != nil {
+		return fmt.Errorf("append to event log: %w", err)
+	}
+
+	return nil
+}
+
+// ReplayLog publishes all events of the EventLog asynchronously, without logging them again.
+// Use it on startup after subscribing to restore events published before a restart.
+// It returns the number of replayed events and ErrNoEventLog if the EventBus has no EventLog.
+func (eb *EventBus) ReplayLog() (int, error) {
+	if eb.log == nil {
+		return 0, ErrNoEventLog
+	}
+
+	o := &publishOptions{replayed: true} //nolint:exhaustivestruct
+	count := 0
+
+	err := eb.log.Replay(func(evt Event) error {
+		if _, err := eb.publishAsync(evt, o); err != nil {
+			return err
+		}
+
+		count++
+
+		return nil
+	})
+
+	return count, err
+}
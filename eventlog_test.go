@@ -0,0 +1,141 @@
+package eventbus_test
+
+import (
+	"errors"
+	eb "github.com/dtomasi/go-event-bus/v3"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errLogFull = errors.New("log is full")
+
+// memoryLog is an EventLog keeping events in memory.
+type memoryLog struct {
+	mu     sync.Mutex
+	events []eb.Event
+	limit  int
+}
+
+func (l *memoryLog) Append(evt eb.Event) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limit > 0 && len(l.events) >= l.limit {
+		return errLogFull
+	}
+
+	l.events = append(l.events, evt)
+
+	return nil
+}
+
+func (l *memoryLog) Replay(fn func(evt eb.Event) error) error {
+	l.mu.Lock()
+	events := append([]eb.Event(nil), l.events...)
+	l.mu.Unlock()
+
+	for _, evt := range events {
+		if err := fn(evt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestEventBus_EventLog(t *testing.T) {
+	log := &memoryLog{limit: 2} //nolint:exhaustivestruct
+	ebi := eb.NewEventBus(eb.WithEventLog(log))
+
+	sub, err := ebi.Subscribe("orders", eb.WithBufferSize(3))
+	assert.NoError(t, err)
+
+	assert.NoError(t, ebi.PublishAsync("orders", 1))
+	assert.NoError(t, ebi.PublishAsync("orders", 2))
+
+	// Events that could not be logged are not delivered
+	assert.ErrorIs(t, ebi.PublishAsync("orders", 3), errLogFull)
+
+	_, err = ebi.Publish("orders", 3)
+	assert.ErrorIs(t, err, errLogFull)
+
+	assert.Equal(t, 1, (<-sub.Channel()).Data)
+	assert.Equal(t, 2, (<-sub.Channel()).Data)
+	assert.Len(t, sub.Channel(), 0)
+	assert.Equal(t, 2, ebi.Stats().GetPublishedCountByTopic("orders"))
+
+	// Replaying delivers the logged events again without logging them
+	count, err := ebi.ReplayLog()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	assert.Equal(t, 1, (<-sub.Channel()).Data)
+	assert.Equal(t, 2, (<-sub.Channel()).Data)
+	assert.Len(t, log.events, 2)
+}
+
+func TestEventBus_ReplayLogWithoutLog(t *testing.T) {
+	_, err := eb.NewEventBus().ReplayLog()
+	assert.ErrorIs(t, err, eb.ErrNoEventLog)
+}
+
+func TestEventBus_EventLogClosed(t *testing.T) {
+	log := &memoryLog{} //nolint:exhaustivestruct
+	ebi := eb.NewEventBus(eb.WithEventLog(log))
+
+	assert.NoError(t, ebi.Close())
+
+	// Events rejected by a closed EventBus are not logged
+	assert.ErrorIs(t, ebi.PublishAsync("orders", 1), eb.ErrClosed)
+
+	_, err := ebi.Publish("orders", 2)
+	assert.ErrorIs(t, err, eb.ErrClosed)
+	assert.Len(t, log.events, 0)
+}
+
+// blockingLog is an EventLog blocking appends until released.
+type blockingLog struct {
+	memoryLog
+	appending chan struct{}
+	release   chan struct{}
+}
+
+func (l *blockingLog) Append(evt eb.Event) error {
+	l.appending <- struct{}{}
+	<-l.release
+
+	return l.memoryLog.Append(evt)
+}
+
+func TestEventBus_EventLogSlowAppend(t *testing.T) {
+	log := &blockingLog{appending: make(chan struct{}), release: make(chan struct{})} //nolint:exhaustivestruct
+	ebi := eb.NewEventBus(eb.WithEventLog(log))
+
+	published := make(chan error)
+
+	go func() {
+		published <- ebi.PublishAsync("orders", 1)
+	}()
+
+	<-log.appending
+
+	// Subscribing does not wait for the append in progress
+	subscribed := make(chan error)
+
+	go func() {
+		_, err := ebi.Subscribe("orders")
+		subscribed <- err
+	}()
+
+	select {
+	case err := <-subscribed:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("subscribing blocked by append")
+	}
+
+	close(log.release)
+	assert.NoError(t, <-published)
+}
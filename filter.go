@@ -0,0 +1,148 @@
+package eventbus
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Filter decides whether an event is delivered to a subscription, see WithFilter.
+type Filter func(evt Event) bool
+
+// HeaderEquals returns a Filter accepting events whose header key holds the value.
+func HeaderEquals(key, value string) Filter {
+	return func(evt Event) bool {
+		v, ok := evt.Headers[key]
+
+		return ok && v == value
+	}
+}
+
+// FieldEquals returns a Filter accepting events whose data holds the value at the dotted path,
+// e.g. "Customer.Country". Path elements name exported struct fields or keys of maps with string keys,
+// pointers and interfaces are dereferenced. Values are compared using reflect.DeepEqual.
+func FieldEquals(path string, value interface{}) Filter {
+	names := strings.Split(path, ".")
+
+	return func(evt Event) bool {
+		v, ok := lookupField(reflect.ValueOf(evt.Data), names)
+
+		return ok && reflect.DeepEqual(v.Interface(), value)
+	}
+}
+
+// lookupField resolves the path elements starting at v.
+func lookupField(v reflect.Value, names []string) (reflect.Value, bool) {
+	for _, name := range names {
+		v = indirect(v)
+
+		switch v.Kind() { //nolint:exhaustive
+		case reflect.Struct:
+			field, ok := v.Type().FieldByName(name)
+			if !ok || field.PkgPath != "" {
+				return reflect.Value{}, false
+			}
+
+			v = v.FieldByIndex(field.Index)
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return reflect.Value{}, false
+			}
+
+			v = v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		default:
+			return reflect.Value{}, false
+		}
+
+		if !v.IsValid() {
+			return reflect.Value{}, false
+		}
+	}
+
+	v = indirect(v)
+
+	return v, v.IsValid() && v.CanInterface()
+}
+
+// indirect dereferences pointers and interfaces, returning the zero Value for nil.
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+
+		v = v.Elem()
+	}
+
+	return v
+}
+
+// accepts reports whether the event passes all filters of the subscription.
+// Panicking filters reject the event, the panic is recovered and passed to the PanicHandler.
+func (eb *EventBus) accepts(sub *Subscription, evt Event) (accepted bool) {
+	if len(sub.filters) == 0 {
+		return true
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			accepted = false
+
+			if eb.panicHandler != nil {
+				eb.panicHandler(sub, evt, newPanicError(r))
+			}
+		}
+	}()
+
+	for _, filter := range sub.filters {
+		if !filter(evt) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// filterSubscriptions returns the subscriptions accepting the event and counts the rejected ones.
+// The matched slice may be cached and shared, so it is copied once a subscription is rejected.
+func (eb *EventBus) filterSubscriptions(subs subscriptionSlice, evt Event) subscriptionSlice {
+	for i, sub := range subs {
+		if eb.accepts(sub, evt) {
+			continue
+		}
+
+		accepted := make(subscriptionSlice, i, len(subs)-1)
+		copy(accepted, subs[:i])
+		eb.stats.incFilteredCountByTopic(evt.Topic)
+
+		for _, sub := range subs[i+1:] {
+			if eb.accepts(sub, evt) {
+				accepted = append(accepted, sub)
+			} else {
+				eb.stats.incFilteredCountByTopic(evt.Topic)
+			}
+		}
+
+		return accepted
+	}
+
+	return subs
+}
+
+// filterEvents returns the retained or replayed events accepted by a new subscription.
+func (eb *EventBus) filterEvents(sub *Subscription, events []Event) []Event {
+	if len(sub.filters) == 0 {
+		return events
+	}
+
+	accepted := events[:0]
+
+	for _, evt := range events {
+		if eb.accepts(sub, evt) {
+			accepted = append(accepted, evt)
+		} else {
+			eb.stats.incFilteredCountByTopic(evt.Topic)
+		}
+	}
+
+	return accepted
+}
@@ -0,0 +1,174 @@
+package eventbus_test
+
+import (
+	"context"
+	eb "github.com/dtomasi/go-event-bus/v3"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type customer struct {
+	Country string
+}
+
+type order struct {
+	ID       int
+	Customer *customer
+	Tags     map[string]string
+}
+
+func TestEventBus_WithFilter(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	even, err := ebi.Subscribe("numbers", eb.WithBufferSize(10), eb.WithFilter(func(evt eb.Event) bool {
+		return evt.Data.(int)%2 == 0
+	}))
+	assert.NoError(t, err)
+
+	all, err := ebi.Subscribe("numbers", eb.WithBufferSize(10))
+	assert.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, ebi.PublishAsync("numbers", i))
+	}
+
+	assert.Equal(t, []interface{}{0, 2, 4}, receiveData(even, 3))
+	assert.Equal(t, []interface{}{0, 1, 2, 3, 4}, receiveData(all, 5))
+	assert.Len(t, even.Channel(), 0)
+	assert.Equal(t, 2, ebi.Stats().GetFilteredCountByTopic("numbers"))
+}
+
+func TestEventBus_WithFilterMultiple(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	sub, err := ebi.Subscribe("numbers", eb.WithBufferSize(10),
+		eb.WithFilter(func(evt eb.Event) bool {
+			return evt.Data.(int) > 1
+		}),
+		eb.WithFilter(func(evt eb.Event) bool {
+			return evt.Data.(int) < 4
+		}),
+	)
+	assert.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, ebi.PublishAsync("numbers", i))
+	}
+
+	assert.Equal(t, []interface{}{2, 3}, receiveData(sub, 2))
+	assert.Len(t, sub.Channel(), 0)
+}
+
+func TestHeaderEquals(t *testing.T) {
+	filter := eb.HeaderEquals("tenant", "acme")
+
+	assert.True(t, filter(eb.Event{Headers: map[string]string{"tenant": "acme"}}))   //nolint:exhaustivestruct
+	assert.False(t, filter(eb.Event{Headers: map[string]string{"tenant": "other"}})) //nolint:exhaustivestruct
+	assert.False(t, filter(eb.Event{}))                                              //nolint:exhaustivestruct
+}
+
+func TestFieldEquals(t *testing.T) {
+	data := order{ID: 1, Customer: &customer{Country: "DE"}, Tags: map[string]string{"channel": "web"}}
+
+	assert.True(t, eb.FieldEquals("ID", 1)(eb.Event{Data: data}))                                    //nolint:exhaustivestruct
+	assert.True(t, eb.FieldEquals("Customer.Country", "DE")(eb.Event{Data: &data}))                  //nolint:exhaustivestruct
+	assert.True(t, eb.FieldEquals("Tags.channel", "web")(eb.Event{Data: data}))                      //nolint:exhaustivestruct
+	assert.False(t, eb.FieldEquals("Customer.Country", "FR")(eb.Event{Data: data}))                  //nolint:exhaustivestruct
+	assert.False(t, eb.FieldEquals("Customer.Missing", "DE")(eb.Event{Data: data}))                  //nolint:exhaustivestruct
+	assert.False(t, eb.FieldEquals("Customer.Country", "DE")(eb.Event{Data: order{}}))               //nolint:exhaustivestruct
+	assert.False(t, eb.FieldEquals("ID", int64(1))(eb.Event{Data: data}))                            //nolint:exhaustivestruct
+	assert.True(t, eb.FieldEquals("kind", "x")(eb.Event{Data: map[string]interface{}{"kind": "x"}})) //nolint:exhaustivestruct
+}
+
+func TestEventBus_FilterHeader(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	var received []interface{}
+
+	_, err := ebi.SubscribeCallback("orders", func(topic string, data interface{}) {
+		received = append(received, data)
+	}, eb.WithFilter(eb.HeaderEquals("tenant", "acme")))
+	assert.NoError(t, err)
+
+	assert.NoError(t, ebi.PublishE("orders", "a", eb.WithHeader("tenant", "acme")))
+	assert.NoError(t, ebi.PublishE("orders", "b", eb.WithHeader("tenant", "other")))
+	assert.NoError(t, ebi.PublishE("orders", "c"))
+
+	assert.Equal(t, []interface{}{"a"}, received)
+	assert.Equal(t, 2, ebi.Stats().GetFilteredCountByTopic("orders"))
+}
+
+func TestEventBus_FilterGroup(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	rejecting, err := ebi.SubscribeGroup("jobs", "workers", eb.WithBufferSize(10), eb.WithFilter(func(evt eb.Event) bool {
+		return false
+	}))
+	assert.NoError(t, err)
+
+	accepting, err := ebi.SubscribeGroup("jobs", "workers", eb.WithBufferSize(10))
+	assert.NoError(t, err)
+
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, ebi.PublishAsync("jobs", i))
+	}
+
+	// Members rejecting an event are not selected
+	assert.Equal(t, []interface{}{0, 1, 2, 3}, receiveData(accepting, 4))
+	assert.Len(t, rejecting.Channel(), 0)
+}
+
+func TestEventBus_FilterNotDeadLettered(t *testing.T) {
+	ebi := eb.NewEventBus(eb.WithDeadLetterTopic("dead"))
+
+	dead, err := ebi.Subscribe("dead", eb.WithBufferSize(1))
+	assert.NoError(t, err)
+
+	_, err = ebi.Subscribe("orders", eb.WithFilter(func(evt eb.Event) bool {
+		return false
+	}))
+	assert.NoError(t, err)
+
+	assert.NoError(t, ebi.PublishE("orders", "order"))
+	assert.Len(t, dead.Channel(), 0)
+	assert.Equal(t, 1, ebi.Stats().GetFilteredCountByTopic("orders"))
+}
+
+func TestEventBus_FilterRetained(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	assert.NoError(t, ebi.PublishAsync("sensors:a", 1, eb.WithRetain()))
+	assert.NoError(t, ebi.PublishAsync("sensors:b", 2, eb.WithRetain()))
+
+	sub, err := ebi.Subscribe("sensors:*", eb.WithBufferSize(2), eb.WithFilter(func(evt eb.Event) bool {
+		return evt.Data.(int) > 1
+	}))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []interface{}{2}, receiveData(sub, 1))
+	assert.Len(t, sub.Channel(), 0)
+	assert.Equal(t, 1, ebi.Stats().GetFilteredCountByTopic("sensors:a"))
+}
+
+func TestEventBus_FilterPanic(t *testing.T) {
+	panics := make(chan *eb.PanicError, 1)
+
+	ebi := eb.NewEventBus(eb.WithPanicHandler(func(sub *eb.Subscription, evt eb.Event, err *eb.PanicError) {
+		panics <- err
+	}))
+
+	sub, err := ebi.Subscribe("numbers", eb.WithBufferSize(1), eb.WithFilter(func(evt eb.Event) bool {
+		return evt.Data.(string) != ""
+	}))
+	assert.NoError(t, err)
+
+	// The panicking filter rejects the event
+	assert.NoError(t, ebi.PublishAsync("numbers", 1))
+	assert.NotNil(t, <-panics)
+	assert.Len(t, sub.Channel(), 0)
+	assert.Equal(t, 1, ebi.Stats().GetFilteredCountByTopic("numbers"))
+
+	abandoned, err := ebi.Drain(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, abandoned)
+}
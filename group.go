@@ -0,0 +1,141 @@
+package eventbus
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync/atomic"
+)
+
+// GroupStrategy defines how a consumer group selects the member receiving an event.
+type GroupStrategy int
+
+const (
+	// GroupRoundRobin selects members in turn.
+	GroupRoundRobin GroupStrategy = iota
+	// GroupLeastLoaded selects the member with the fewest queued and in-flight events.
+	GroupLeastLoaded
+	// GroupRandom selects a random member.
+	GroupRandom
+)
+
+// consumerGroup shares the events of a topic between its members.
+type consumerGroup struct {
+	name     string
+	strategy GroupStrategy
+	members  int
+	next     uint64
+}
+
+// SubscribeGroup subscribes to a topic as member of a consumer group.
+// Every event is delivered to exactly one member of each group, while subscriptions
+// without a group still receive every event. See WithGroup for callbacks.
+func (eb *EventBus) SubscribeGroup(topic, group string, opts ...SubscribeOption) (*Subscription, error) {
+	return eb.Subscribe(topic, append(opts, WithGroup(group))...)
+}
+
+// joinGroup adds a subscription to its consumer group, creating the group if needed.
+// The caller must hold the lock.
+func (eb *EventBus) joinGroup(group string, strategy GroupStrategy) error {
+	g, ok := eb.groups[group]
+	if !ok {
+		g = &consumerGroup{ //nolint:exhaustivestruct
+			name:     group,
+			strategy: strategy,
+		}
+		eb.groups[group] = g
+	}
+
+	if g.strategy != strategy {
+		return fmt.Errorf("%w: group %q", ErrGroupStrategyMismatch, group)
+	}
+
+	g.members++
+
+	eb.stats.incGroupMemberCount(group)
+
+	return nil
+}
+
+// leaveGroup removes a subscription from its consumer group and deletes empty groups.
+// The caller must hold the lock.
+func (eb *EventBus) leaveGroup(sub *Subscription) {
+	g, ok := eb.groups[sub.group]
+	if !ok {
+		return
+	}
+
+	g.members--
+
+	if g.members == 0 {
+		delete(eb.groups, sub.group)
+	}
+
+	eb.stats.decGroupMemberCount(sub.group)
+}
+
+// selectGroupMembers keeps all subscriptions without a group and selects one member of every group.
+// The caller must hold the lock.
+func (eb *EventBus) selectGroupMembers(subs subscriptionSlice) subscriptionSlice {
+	var (
+		selected subscriptionSlice
+		members  map[string]subscriptionSlice
+	)
+
+	for _, sub := range subs {
+		if sub.group == "" {
+			selected = append(selected, sub)
+
+			continue
+		}
+
+		if members == nil {
+			members = map[string]subscriptionSlice{}
+		}
+
+		members[sub.group] = append(members[sub.group], sub)
+	}
+
+	if members == nil {
+		return subs
+	}
+
+	// Groups are served in a stable order.
+	groups := make([]string, 0, len(members))
+	for group := range members {
+		groups = append(groups, group)
+	}
+
+	sort.Strings(groups)
+
+	for _, group := range groups {
+		selected = append(selected, eb.groups[group].selectMember(members[group]))
+
+		eb.stats.incGroupDeliveredCount(group)
+	}
+
+	sortByPriority(selected)
+
+	return selected
+}
+
+// selectMember selects the member receiving an event according to the group strategy.
+func (g *consumerGroup) selectMember(members subscriptionSlice) *Subscription {
+	switch g.strategy {
+	case GroupLeastLoaded:
+		selected := members[0]
+		for _, member := range members[1:] {
+			if member.load() < selected.load() {
+				selected = member
+			}
+		}
+
+		return selected
+	case GroupRandom:
+		return members[rand.Intn(len(members))] //nolint:gosec
+	case GroupRoundRobin:
+		fallthrough
+	default:
+		return members[(atomic.AddUint64(&g.next, 1)-1)%uint64(len(members))]
+	}
+}
@@ -0,0 +1,108 @@
+package eventbus_test
+
+import (
+	"context"
+	eb "github.com/dtomasi/go-event-bus/v3"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEventBus_SubscribeGroup(t *testing.T) {
+	const count = 9
+
+	ebi := eb.NewEventBus()
+
+	var members []*eb.Subscription
+
+	for i := 0; i < 3; i++ {
+		member, err := ebi.SubscribeGroup("foo:+", "workers", eb.WithBufferSize(count))
+		assert.NoError(t, err)
+
+		members = append(members, member)
+	}
+
+	broadcast, err := ebi.Subscribe("foo:+", eb.WithBufferSize(count))
+	assert.NoError(t, err)
+
+	for i := 0; i < count; i++ {
+		assert.NoError(t, ebi.PublishAsync("foo:bar", i))
+	}
+
+	_, err = ebi.Drain(context.Background())
+	assert.NoError(t, err)
+
+	// Round robin shares the events equally
+	for _, member := range members {
+		assert.Len(t, member.Channel(), 3)
+		assert.Equal(t, "workers", member.Group())
+	}
+
+	// Subscriptions without group receive every event
+	assert.Len(t, broadcast.Channel(), count)
+
+	gStats := ebi.Stats().GetGroupStatsByName("workers")
+	assert.Equal(t, 3, gStats.MemberCount.Value())
+	assert.Equal(t, count, gStats.DeliveredCount.Value())
+
+	members[0].Unsubscribe()
+
+	assert.Equal(t, 2, gStats.MemberCount.Value())
+}
+
+func TestEventBus_SubscribeGroupStrategyMismatch(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	_, err := ebi.SubscribeGroup("foo", "workers")
+	assert.NoError(t, err)
+
+	_, err = ebi.SubscribeGroup("foo", "workers", eb.WithGroupStrategy(eb.GroupRandom))
+	assert.ErrorIs(t, err, eb.ErrGroupStrategyMismatch)
+}
+
+func TestEventBus_SubscribeGroupLeastLoaded(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	busy, err := ebi.SubscribeGroup("foo", "workers",
+		eb.WithBufferSize(10), eb.WithGroupStrategy(eb.GroupLeastLoaded))
+	assert.NoError(t, err)
+
+	idle := eb.NewSafeCounter()
+
+	_, err = ebi.SubscribeCallback("foo", func(topic string, data interface{}) {
+		idle.Inc()
+	}, eb.WithGroup("workers"), eb.WithGroupStrategy(eb.GroupLeastLoaded))
+	assert.NoError(t, err)
+
+	// The first event goes to the first member, all others to the idle callback
+	assert.NoError(t, ebi.PublishAsync("foo", 0))
+
+	for i := 1; i < 5; i++ {
+		_, err = ebi.Publish("foo", i)
+		assert.NoError(t, err)
+	}
+
+	assert.Len(t, busy.Channel(), 1)
+	assert.Equal(t, 4, idle.Value())
+}
+
+func TestEventBus_SubscribeGroupRandom(t *testing.T) {
+	const count = 20
+
+	ebi := eb.NewEventBus()
+
+	received := eb.NewSafeCounter()
+
+	for i := 0; i < 3; i++ {
+		_, err := ebi.SubscribeCallback("foo", func(topic string, data interface{}) {
+			received.Inc()
+		}, eb.WithGroup("workers"), eb.WithGroupStrategy(eb.GroupRandom))
+		assert.NoError(t, err)
+	}
+
+	for i := 0; i < count; i++ {
+		_, err := ebi.Publish("foo", i)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, count, received.Value())
+}
@@ -0,0 +1,133 @@
+package eventbus
+
+import (
+	"sync/atomic"
+)
+
+// CallbackFunc Defines a CallbackFunc.
+type CallbackFunc func(topic string, data interface{})
+
+// HandlerFunc defines a callback that is able to report an error.
+// Errors are returned to publishers using PublishE.
+type HandlerFunc func(topic string, data interface{}) error
+
+// EventHandler handles an Event, all callback types are wrapped into an EventHandler.
+// A returned error fails the event, see Event.Fail.
+type EventHandler func(evt Event) error
+
+// SubscribeCallback provides a simple wrapper that allows to directly register CallbackFunc instead of channels.
+// The callback is invoked for every event until the Subscription is removed.
+// Use WithConcurrency to invoke the callback from multiple workers.
+func (eb *EventBus) SubscribeCallback(
+	topic string,
+	callable CallbackFunc,
+	opts ...SubscribeOption,
+) (*Subscription, error) {
+	return eb.subscribeHandler(topic, func(evt Event) error {
+		callable(evt.Topic, evt.Data)
+
+		return nil
+	}, opts)
+}
+
+// SubscribeHandler same as SubscribeCallback but for handlers returning an error.
+// A returned error fails the event, see Event.Fail.
+func (eb *EventBus) SubscribeHandler(
+	topic string,
+	handler HandlerFunc,
+	opts ...SubscribeOption,
+) (*Subscription, error) {
+	return eb.subscribeHandler(topic, func(evt Event) error {
+		return handler(evt.Topic, evt.Data)
+	}, opts)
+}
+
+// SubscribeEvent same as SubscribeHandler but the handler receives the whole Event
+// including its metadata like ID, Timestamp, Source and Headers.
+func (eb *EventBus) SubscribeEvent(
+	topic string,
+	handler EventHandler,
+	opts ...SubscribeOption,
+) (*Subscription, error) {
+	return eb.subscribeHandler(topic, handler, opts)
+}
+
+// subscribeHandler subscribes using the handler, workers are started by subscribe.
+func (eb *EventBus) subscribeHandler(
+	topic string,
+	handler EventHandler,
+	opts []SubscribeOption,
+) (*Subscription, error) {
+	o := newSubscribeOptions(opts)
+	o.handled = true
+	o.handler = eb.wrapHandler(handler, o.middleware)
+
+	return eb.subscribe(topic, o.newChannel(), true, o)
+}
+
+// startHandlers starts the workers invoking the handler of the subscription.
+func (eb *EventBus) startHandlers(sub *Subscription, o *subscribeOptions) {
+	if o.partitions > 0 {
+		go eb.runPartitioned(sub, o.handler, o.partitionKey, eb.stats.addPartitionStats(sub, o.partitions))
+
+		return
+	}
+
+	for i := 0; i < o.concurrency; i++ {
+		go eb.runHandler(sub, o.handler)
+	}
+}
+
+// runHandler invokes the handler for every event received by the Subscription until it is stopped.
+func (eb *EventBus) runHandler(sub *Subscription, handler EventHandler) {
+	for {
+		select {
+		case evt, ok := <-sub.ch:
+			if !ok {
+				return
+			}
+
+			eb.invokeHandler(sub, handler, evt)
+		case <-sub.done:
+			return
+		}
+	}
+}
+
+// invokeHandler calls the handler and makes sure the event is acknowledged even if the handler panics.
+// Failed calls are retried according to the RetryPolicy of the subscription.
+func (eb *EventBus) invokeHandler(sub *Subscription, handler EventHandler, evt Event) {
+	atomic.AddInt32(&sub.active, 1)
+	defer atomic.AddInt32(&sub.active, -1)
+
+	err := eb.callHandler(sub, handler, evt)
+	if err != nil && sub.retryPolicy != nil {
+		var attempts int
+
+		attempts, err = eb.retryHandler(sub, handler, evt, err)
+		evt.retries = attempts - 1
+	}
+
+	evt.Fail(err)
+}
+
+// callHandler calls the handler once within a span if the EventBus has a Tracer.
+// Panics are recovered, passed to the PanicHandler and reported as *PanicError.
+func (eb *EventBus) callHandler(sub *Subscription, handler EventHandler, evt Event) (err error) {
+	evt, span := eb.traceHandler(sub, evt)
+
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := newPanicError(r)
+			err = panicErr
+
+			if eb.panicHandler != nil {
+				eb.panicHandler(sub, evt, panicErr)
+			}
+		}
+
+		endSpan(span, err)
+	}()
+
+	return handler(evt)
+}
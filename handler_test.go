@@ -0,0 +1,73 @@
+package eventbus_test
+
+import (
+	"errors"
+	eb "github.com/dtomasi/go-event-bus/v3"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var errTest = errors.New("test error")
+
+func TestEventBus_SubscribeHandler(t *testing.T) {
+	const testTopicName = "foo:bar"
+
+	ebi := eb.NewEventBus()
+
+	failing, err := ebi.SubscribeHandler(testTopicName, func(topic string, data interface{}) error {
+		return errTest
+	})
+	assert.NoError(t, err)
+
+	_, err = ebi.SubscribeHandler("foo:*", func(topic string, data interface{}) error {
+		return nil
+	})
+	assert.NoError(t, err)
+
+	// Channel subscribers report errors using Fail
+	ch, err := ebi.Subscribe("foo:*")
+	assert.NoError(t, err)
+
+	go func() {
+		evt := <-ch.Channel()
+		evt.Fail(errTest)
+	}()
+
+	err = ebi.PublishE(testTopicName, "bar")
+
+	var pubErr *eb.PublishError
+
+	assert.ErrorAs(t, err, &pubErr)
+	assert.ErrorIs(t, err, errTest)
+	assert.Equal(t, testTopicName, pubErr.Topic)
+	assert.Len(t, pubErr.Errors, 2)
+
+	var subErr *eb.SubscriberError
+
+	assert.ErrorAs(t, pubErr.Errors[0], &subErr)
+	assert.Equal(t, failing.ID(), subErr.Subscription.ID())
+
+	assert.ErrorAs(t, pubErr.Errors[1], &subErr)
+	assert.Equal(t, ch.ID(), subErr.Subscription.ID())
+
+	ch.Unsubscribe()
+
+	// Publish does not report subscriber errors
+	_, err = ebi.Publish("foo:baz", "bar")
+	assert.NoError(t, err)
+}
+
+func TestEventBus_PublishE(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	_, err := ebi.SubscribeHandler("foo", func(topic string, data interface{}) error {
+		return nil
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, ebi.PublishE("foo", "bar"))
+	assert.NoError(t, ebi.PublishE("no-subscribers", "bar"))
+
+	assert.NoError(t, ebi.Close())
+	assert.ErrorIs(t, ebi.PublishE("foo", "bar"), eb.ErrClosed)
+}
@@ -0,0 +1,197 @@
+package eventbus
+
+import (
+	"strings"
+	"sync"
+)
+
+// matchCacheSize is the maximum number of topics kept in the match cache before it is reset.
+const matchCacheSize = 1024
+
+// topicIndex finds the subscription patterns matching a published topic.
+type topicIndex interface {
+	// add registers a pattern.
+	add(pattern string)
+	// remove unregisters a pattern.
+	remove(pattern string)
+	// match returns all registered patterns matching the topic.
+	match(topic string) []string
+}
+
+// newTopicIndex returns a trie based index for segment matchers and a linear index for all other matchers.
+func newTopicIndex(matcher TopicMatcher) topicIndex {
+	if segmentMatcher, ok := matcher.(*SegmentMatcher); ok {
+		return newTrieIndex(segmentMatcher.Separator())
+	}
+
+	return newLinearIndex(matcher)
+}
+
+// linearIndex matches a topic against every registered pattern.
+type linearIndex struct {
+	matcher  TopicMatcher
+	patterns map[string]struct{}
+}
+
+func newLinearIndex(matcher TopicMatcher) *linearIndex {
+	return &linearIndex{
+		matcher:  matcher,
+		patterns: map[string]struct{}{},
+	}
+}
+
+func (idx *linearIndex) add(pattern string) {
+	idx.patterns[pattern] = struct{}{}
+}
+
+func (idx *linearIndex) remove(pattern string) {
+	delete(idx.patterns, pattern)
+}
+
+func (idx *linearIndex) match(topic string) []string {
+	var patterns []string
+
+	for pattern := range idx.patterns {
+		if pattern == topic || idx.matcher.Match(pattern, topic) {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	return patterns
+}
+
+// trieNode is a single segment of the trieIndex.
+type trieNode struct {
+	children map[string]*trieNode
+	// pattern is set if a registered pattern ends at this node.
+	pattern string
+}
+
+func newTrieNode() *trieNode {
+	return &trieNode{ //nolint:exhaustivestruct
+		children: map[string]*trieNode{},
+	}
+}
+
+// trieIndex stores patterns segment by segment, so matching only visits
+// the branches that can match the topic instead of every registered pattern.
+type trieIndex struct {
+	separator string
+	root      *trieNode
+}
+
+func newTrieIndex(separator string) *trieIndex {
+	return &trieIndex{
+		separator: separator,
+		root:      newTrieNode(),
+	}
+}
+
+func (idx *trieIndex) add(pattern string) {
+	node := idx.root
+
+	for _, segment := range strings.Split(pattern, idx.separator) {
+		child, ok := node.children[segment]
+		if !ok {
+			child = newTrieNode()
+			node.children[segment] = child
+		}
+
+		node = child
+	}
+
+	node.pattern = pattern
+}
+
+func (idx *trieIndex) remove(pattern string) {
+	idx.removeSegments(idx.root, strings.Split(pattern, idx.separator))
+}
+
+// removeSegments removes the pattern below node and prunes empty nodes.
+// It reports whether node itself became empty.
+func (idx *trieIndex) removeSegments(node *trieNode, segments []string) bool {
+	if len(segments) == 0 {
+		node.pattern = ""
+	} else if child, ok := node.children[segments[0]]; ok && idx.removeSegments(child, segments[1:]) {
+		delete(node.children, segments[0])
+	}
+
+	return node.pattern == "" && len(node.children) == 0
+}
+
+func (idx *trieIndex) match(topic string) []string {
+	var patterns []string
+
+	idx.matchSegments(idx.root, strings.Split(topic, idx.separator), &patterns)
+
+	return patterns
+}
+
+func (idx *trieIndex) matchSegments(node *trieNode, segments []string, patterns *[]string) {
+	// A multi level wildcard matches the parent and all segments below.
+	if child, ok := node.children[MultiLevelWildcard]; ok && child.pattern != "" {
+		*patterns = append(*patterns, child.pattern)
+	}
+
+	if len(segments) == 0 {
+		if node.pattern != "" {
+			*patterns = append(*patterns, node.pattern)
+		}
+
+		return
+	}
+
+	keys := []string{segments[0]}
+
+	for _, wildcard := range []string{SingleLevelWildcard, globWildcard} {
+		if wildcard != segments[0] {
+			keys = append(keys, wildcard)
+		}
+	}
+
+	for _, key := range keys {
+		if child, ok := node.children[key]; ok {
+			idx.matchSegments(child, segments[1:], patterns)
+		}
+	}
+}
+
+// matchCache caches the subscriptions matching a topic.
+// It must be reset whenever subscriptions are added or removed.
+type matchCache struct {
+	mu      sync.Mutex
+	entries map[string]subscriptionSlice
+}
+
+func newMatchCache() *matchCache {
+	return &matchCache{ //nolint:exhaustivestruct
+		entries: map[string]subscriptionSlice{},
+	}
+}
+
+func (c *matchCache) get(topic string) (subscriptionSlice, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	subs, ok := c.entries[topic]
+
+	return subs, ok
+}
+
+func (c *matchCache) set(topic string, subs subscriptionSlice) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= matchCacheSize {
+		c.entries = map[string]subscriptionSlice{}
+	}
+
+	c.entries[topic] = subs
+}
+
+func (c *matchCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = map[string]subscriptionSlice{}
+}
@@ -0,0 +1,120 @@
+package eventbus_test
+
+import (
+	"fmt"
+	eb "github.com/dtomasi/go-event-bus/v3"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEventBus_MatchCacheInvalidation(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	sub, err := ebi.Subscribe("foo:+")
+	assert.NoError(t, err)
+
+	assert.True(t, ebi.HasSubscribers("foo:bar"))
+	assert.False(t, ebi.HasSubscribers("foo:bar:baz"))
+
+	other, err := ebi.Subscribe("foo:#")
+	assert.NoError(t, err)
+
+	assert.True(t, ebi.HasSubscribers("foo:bar:baz"))
+
+	sub.Unsubscribe()
+	other.Unsubscribe()
+
+	assert.False(t, ebi.HasSubscribers("foo:bar"))
+	assert.False(t, ebi.HasSubscribers("foo:bar:baz"))
+
+	_, err = ebi.Subscribe("+:bar")
+	assert.NoError(t, err)
+
+	assert.True(t, ebi.HasSubscribers("foo:bar"))
+}
+
+// newBenchmarkEventBus returns an EventBus with count subscriptions not matching any benchmark topic.
+func newBenchmarkEventBus(b *testing.B, count int, opts ...eb.Option) *eb.EventBus {
+	b.Helper()
+
+	ebi := eb.NewEventBus(opts...)
+
+	for i := 0; i < count; i++ {
+		if _, err := ebi.Subscribe(fmt.Sprintf("other:%d:*", i)); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	if _, err := ebi.Subscribe("bench:+:topic"); err != nil {
+		b.Fatal(err)
+	}
+
+	return ebi
+}
+
+// benchmarkTopics returns more distinct topics than the match cache holds.
+func benchmarkTopics() []string {
+	topics := make([]string, 4096)
+	for i := range topics {
+		topics[i] = fmt.Sprintf("bench:%d:topic", i)
+	}
+
+	return topics
+}
+
+func BenchmarkEventBus_Match(b *testing.B) {
+	topics := benchmarkTopics()
+
+	for _, count := range []int{10, 100, 1000, 10000} {
+		b.Run(fmt.Sprintf("trie/topics=%d", count), func(b *testing.B) {
+			ebi := newBenchmarkEventBus(b, count)
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				ebi.HasSubscribers(topics[i%len(topics)])
+			}
+		})
+
+		b.Run(fmt.Sprintf("glob/topics=%d", count), func(b *testing.B) {
+			ebi := newBenchmarkEventBus(b, count, eb.WithTopicMatcher(eb.NewGlobMatcher()))
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				ebi.HasSubscribers(topics[i%len(topics)])
+			}
+		})
+
+		b.Run(fmt.Sprintf("cached/topics=%d", count), func(b *testing.B) {
+			ebi := newBenchmarkEventBus(b, count)
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				ebi.HasSubscribers(topics[0])
+			}
+		})
+	}
+}
+
+func BenchmarkEventBus_Publish(b *testing.B) {
+	for _, matching := range []int{1, 10} {
+		for _, count := range []int{10, 100, 1000, 10000} {
+			b.Run(fmt.Sprintf("subscribers=%d/topics=%d", matching, count), func(b *testing.B) {
+				ebi := newBenchmarkEventBus(b, count)
+
+				for i := 0; i < matching; i++ {
+					if _, err := ebi.SubscribeCallback("orders:+", func(topic string, data interface{}) {}); err != nil {
+						b.Fatal(err)
+					}
+				}
+
+				b.ResetTimer()
+
+				for i := 0; i < b.N; i++ {
+					if _, err := ebi.Publish("orders:created", i); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
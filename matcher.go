@@ -0,0 +1,160 @@
+package eventbus
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultSeparator is the topic segment separator used by default.
+	DefaultSeparator = ":"
+	// SingleLevelWildcard matches exactly one topic segment.
+	SingleLevelWildcard = "+"
+	// MultiLevelWildcard matches any number of trailing topic segments. It must be the last segment of a pattern.
+	MultiLevelWildcard = "#"
+	// globWildcard matches any sequence of characters using the GlobMatcher.
+	// The SegmentMatcher treats it as SingleLevelWildcard.
+	globWildcard = "*"
+)
+
+// TopicMatcher matches published topics against subscription patterns.
+type TopicMatcher interface {
+	// Validate returns an error wrapping ErrInvalidPattern if the pattern is malformed.
+	Validate(pattern string) error
+	// Match reports whether the topic matches the pattern.
+	Match(pattern, topic string) bool
+}
+
+// SegmentMatcher matches topics segment by segment, similar to MQTT.
+// Topics are split into segments by the separator. A "+" (or "*") segment matches exactly one segment,
+// a trailing "#" segment matches the parent segment and any number of segments below it.
+// For example, using ":" as separator "foo:+" matches "foo:bar" but not "foo:bar:baz",
+// "foo:#" matches "foo", "foo:bar" and "foo:bar:baz".
+type SegmentMatcher struct {
+	separator string
+}
+
+// NewSegmentMatcher returns a SegmentMatcher using the given separator, e.g. ":", "." or "/".
+func NewSegmentMatcher(separator string) *SegmentMatcher {
+	return &SegmentMatcher{
+		separator: separator,
+	}
+}
+
+// Separator returns the segment separator.
+func (m *SegmentMatcher) Separator() string {
+	return m.separator
+}
+
+// Validate makes sure wildcards occupy whole segments and "#" is only used as last segment.
+func (m *SegmentMatcher) Validate(pattern string) error {
+	if pattern == "" {
+		return fmt.Errorf("%w: empty pattern", ErrInvalidPattern)
+	}
+
+	segments := strings.Split(pattern, m.separator)
+
+	for i, segment := range segments {
+		if len(segment) > 1 && strings.ContainsAny(segment, SingleLevelWildcard+MultiLevelWildcard+globWildcard) {
+			return fmt.Errorf("%w: %q: wildcard must occupy a whole segment", ErrInvalidPattern, pattern)
+		}
+
+		if segment == MultiLevelWildcard && i != len(segments)-1 {
+			return fmt.Errorf("%w: %q: %q must be the last segment", ErrInvalidPattern, pattern, MultiLevelWildcard)
+		}
+	}
+
+	return nil
+}
+
+// validateTopic makes sure a published topic contains no wildcard segments.
+func (m *SegmentMatcher) validateTopic(topic string) error {
+	for _, segment := range strings.Split(topic, m.separator) {
+		switch segment {
+		case SingleLevelWildcard, MultiLevelWildcard, globWildcard:
+			return fmt.Errorf("%w: %q: published topics must not contain wildcards", ErrInvalidPattern, topic)
+		}
+	}
+
+	return nil
+}
+
+// Match reports whether the topic matches the pattern.
+func (m *SegmentMatcher) Match(pattern, topic string) bool {
+	patternSegments := strings.Split(pattern, m.separator)
+	topicSegments := strings.Split(topic, m.separator)
+
+	for i, segment := range patternSegments {
+		switch segment {
+		case MultiLevelWildcard:
+			return true
+		case SingleLevelWildcard, globWildcard:
+			if i >= len(topicSegments) {
+				return false
+			}
+		default:
+			if i >= len(topicSegments) || topicSegments[i] != segment {
+				return false
+			}
+		}
+	}
+
+	return len(patternSegments) == len(topicSegments)
+}
+
+// GlobMatcher matches topics using "*" wildcards that match any sequence of characters,
+// including separators. "foo:*" matches "foo:bar" as well as "foo:bar:baz".
+type GlobMatcher struct{}
+
+// NewGlobMatcher returns a GlobMatcher.
+func NewGlobMatcher() *GlobMatcher {
+	return &GlobMatcher{}
+}
+
+// Validate only rejects empty patterns, all other patterns are valid.
+func (m *GlobMatcher) Validate(pattern string) error {
+	if pattern == "" {
+		return fmt.Errorf("%w: empty pattern", ErrInvalidPattern)
+	}
+
+	return nil
+}
+
+// Match reports whether the topic matches the pattern.
+func (m *GlobMatcher) Match(pattern, topic string) bool {
+	return pattern == topic || matchWildcard(pattern, topic)
+}
+
+// Code from https://github.com/minio/minio/blob/master/pkg/wildcard/match.go
+func matchWildcard(pattern, name string) bool {
+	if pattern == "" {
+		return name == pattern
+	}
+
+	if pattern == "*" {
+		return true
+	}
+	// Does only wildcard '*' match.
+	return deepMatchRune([]rune(name), []rune(pattern), true)
+}
+
+// Code from https://github.com/minio/minio/blob/master/pkg/wildcard/match.go
+func deepMatchRune(str, pattern []rune, simple bool) bool { //nolint:unparam
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		default:
+			if len(str) == 0 || str[0] != pattern[0] {
+				return false
+			}
+		case '*':
+			return deepMatchRune(str, pattern[1:], simple) ||
+				(len(str) > 0 && deepMatchRune(str[1:], pattern, simple))
+		}
+
+		str = str[1:]
+
+		pattern = pattern[1:]
+	}
+
+	return len(str) == 0 && len(pattern) == 0
+}
@@ -0,0 +1,101 @@
+package eventbus_test
+
+import (
+	eb "github.com/dtomasi/go-event-bus/v3"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSegmentMatcher_Match(t *testing.T) {
+	matcher := eb.NewSegmentMatcher(":")
+
+	tests := []struct {
+		pattern string
+		topic   string
+		match   bool
+	}{
+		{"foo:bar", "foo:bar", true},
+		{"foo:bar", "foo:baz", false},
+		{"foo:+", "foo:bar", true},
+		{"foo:*", "foo:bar", true},
+		{"foo:+", "foo:bar:baz", false},
+		{"foo:*", "foo:bar:baz", false},
+		{"foo:+", "foo", false},
+		{"+:bar", "foo:bar", true},
+		{"foo:+:baz", "foo:bar:baz", true},
+		{"foo:#", "foo", true},
+		{"foo:#", "foo:bar", true},
+		{"foo:#", "foo:bar:baz", true},
+		{"foo:#", "bar:baz", false},
+		{"#", "foo:bar", true},
+		{"foo", "foo:bar", false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.match, matcher.Match(test.pattern, test.topic), "%s -> %s", test.pattern, test.topic)
+	}
+}
+
+func TestSegmentMatcher_Validate(t *testing.T) {
+	matcher := eb.NewSegmentMatcher("/")
+
+	for _, pattern := range []string{"foo", "foo/+", "+/bar/#", "#", "foo/*"} {
+		assert.NoError(t, matcher.Validate(pattern), pattern)
+	}
+
+	for _, pattern := range []string{"", "foo/#/bar", "foo/ba+", "foo/#bar", "foo*"} {
+		assert.ErrorIs(t, matcher.Validate(pattern), eb.ErrInvalidPattern, pattern)
+	}
+}
+
+func TestGlobMatcher_Match(t *testing.T) {
+	matcher := eb.NewGlobMatcher()
+
+	assert.True(t, matcher.Match("foo:*", "foo:bar"))
+	assert.True(t, matcher.Match("foo:*", "foo:bar:baz"))
+	assert.True(t, matcher.Match("*", "foo"))
+	assert.True(t, matcher.Match("foo", "foo"))
+	assert.False(t, matcher.Match("foo:*", "bar:baz"))
+	assert.NoError(t, matcher.Validate("foo:*bar"))
+}
+
+func TestEventBus_WithSeparator(t *testing.T) {
+	ebi := eb.NewEventBus(eb.WithSeparator("."))
+
+	_, err := ebi.Subscribe("foo.+")
+	assert.NoError(t, err)
+
+	assert.True(t, ebi.HasSubscribers("foo.bar"))
+	assert.False(t, ebi.HasSubscribers("foo.bar.baz"))
+	assert.False(t, ebi.HasSubscribers("foo:bar"))
+
+	_, err = ebi.Subscribe("foo.#.bar")
+	assert.ErrorIs(t, err, eb.ErrInvalidPattern)
+}
+
+func TestEventBus_WithGlobMatcher(t *testing.T) {
+	ebi := eb.NewEventBus(eb.WithTopicMatcher(eb.NewGlobMatcher()))
+
+	_, err := ebi.Subscribe("foo:*")
+	assert.NoError(t, err)
+
+	assert.True(t, ebi.HasSubscribers("foo:bar:baz"))
+}
+
+func TestEventBus_PublishWildcardTopic(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	sub, err := ebi.Subscribe("foo:#", eb.WithBufferSize(2))
+	assert.NoError(t, err)
+
+	// Wildcards are only allowed in subscription patterns
+	for _, topic := range []string{"foo:#", "foo:+", "foo:*:bar"} {
+		assert.ErrorIs(t, ebi.PublishAsync(topic, "bar"), eb.ErrInvalidPattern, topic)
+
+		_, err = ebi.Publish(topic, "bar")
+		assert.ErrorIs(t, err, eb.ErrInvalidPattern, topic)
+	}
+
+	assert.Len(t, sub.Channel(), 0)
+	assert.Equal(t, 0, ebi.Stats().GetPublishedCountByTopic("foo:#"))
+}
@@ -0,0 +1,54 @@
+package eventbus
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+)
+
+// stamp sets the metadata of a published event. Events replayed from an EventLog keep their metadata.
+func (o *publishOptions) stamp(evt Event) Event {
+	if o.id != "" {
+		evt.ID = o.id
+	}
+
+	if o.source != "" {
+		evt.Source = o.source
+	}
+
+	if len(o.headers) > 0 {
+		headers := make(map[string]string, len(evt.Headers)+len(o.headers))
+		for key, value := range evt.Headers {
+			headers[key] = value
+		}
+
+		for key, value := range o.headers {
+			headers[key] = value
+		}
+
+		evt.Headers = headers
+	}
+
+	if evt.ID == "" {
+		evt.ID = newEventID()
+	}
+
+	if evt.Timestamp.IsZero() {
+		evt.Timestamp = time.Now()
+	}
+
+	return evt
+}
+
+// newEventID returns a random (version 4) UUID.
+func newEventID() string {
+	var b [16]byte
+
+	// crypto/rand does not fail on supported platforms.
+	_, _ = rand.Read(b[:])
+
+	b[6] = (b[6] & 0x0f) | 0x40 //nolint:gomnd
+	b[8] = (b[8] & 0x3f) | 0x80 //nolint:gomnd
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
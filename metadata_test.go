@@ -0,0 +1,100 @@
+package eventbus_test
+
+import (
+	"context"
+	eb "github.com/dtomasi/go-event-bus/v3"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestEventBus_Metadata(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	sub, err := ebi.Subscribe("orders", eb.WithBufferSize(2))
+	assert.NoError(t, err)
+
+	before := time.Now()
+
+	assert.NoError(t, ebi.PublishAsync("orders", "a",
+		eb.WithEventID("order-1"),
+		eb.WithSource("shop"),
+		eb.WithHeader("tenant", "acme"),
+		eb.WithHeaders(map[string]string{"region": "eu"}),
+	))
+	assert.NoError(t, ebi.PublishAsync("orders", "b"))
+
+	evt := <-sub.Channel()
+	assert.Equal(t, "order-1", evt.ID)
+	assert.Equal(t, "shop", evt.Source)
+	assert.Equal(t, map[string]string{"tenant": "acme", "region": "eu"}, evt.Headers)
+	assert.Equal(t, "acme", evt.Header("tenant"))
+	assert.False(t, evt.Timestamp.Before(before))
+
+	// Events get a random ID by default
+	evt = <-sub.Channel()
+	assert.Len(t, evt.ID, 36)
+	assert.NotEqual(t, "order-1", evt.ID)
+	assert.Empty(t, evt.Header("tenant"))
+}
+
+func TestEventBus_SubscribeEvent(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	var received eb.Event
+
+	_, err := ebi.SubscribeEvent("orders:*", func(evt eb.Event) error {
+		received = evt
+
+		return nil
+	})
+	assert.NoError(t, err)
+
+	_, err = ebi.Publish("orders:created", "order", eb.WithEventID("order-1"), eb.WithHeader("tenant", "acme"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "orders:created", received.Topic)
+	assert.Equal(t, "order", received.Data)
+	assert.Equal(t, "order-1", received.ID)
+	assert.Equal(t, "acme", received.Header("tenant"))
+}
+
+func TestEventBus_MetadataPropagation(t *testing.T) {
+	ebi := eb.NewEventBus(eb.WithReplayBuffer("orders", 10, 0), eb.WithDeadLetterTopic("dead"))
+
+	// Middleware can enrich the metadata
+	ebi.Use(func(next eb.PublishHandler) eb.PublishHandler {
+		return func(evt eb.Event) error {
+			if evt.Source == "" {
+				evt.Source = "middleware"
+			}
+
+			return next(evt)
+		}
+	})
+
+	assert.NoError(t, ebi.PublishAsync("orders", "a", eb.WithEventID("order-1"), eb.WithRetain()))
+
+	evt, ok := ebi.Retained("orders")
+	assert.True(t, ok)
+	assert.Equal(t, "order-1", evt.ID)
+	assert.Equal(t, "middleware", evt.Source)
+
+	replayed, err := ebi.Subscribe("orders", eb.WithReplayLast(1))
+	assert.NoError(t, err)
+	assert.Equal(t, "order-1", (<-replayed.Channel()).ID)
+
+	dead, err := ebi.Subscribe("dead", eb.WithBufferSize(1))
+	assert.NoError(t, err)
+
+	_, err = ebi.SubscribeEvent("failing", func(evt eb.Event) error {
+		return errTest
+	})
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, ebi.PublishECtx(context.Background(), "failing", "b", eb.WithEventID("order-2")), errTest)
+
+	letter, ok := (<-dead.Channel()).Data.(eb.DeadLetter)
+	assert.True(t, ok)
+	assert.Equal(t, "order-2", letter.Event.ID)
+}
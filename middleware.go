@@ -0,0 +1,56 @@
+package eventbus
+
+// PublishHandler publishes an Event.
+type PublishHandler func(evt Event) error
+
+// PublishMiddleware intercepts publishing. It may modify the event, for example its topic to reroute it,
+// before passing it to next, or reject it by returning an error without calling next.
+type PublishMiddleware func(next PublishHandler) PublishHandler
+
+// HandlerMiddleware wraps the invocation of callbacks and handlers.
+type HandlerMiddleware func(next EventHandler) EventHandler
+
+// Use adds middleware intercepting all subsequently published events.
+// Middleware is applied in the order it was added, the first one being the outermost.
+func (eb *EventBus) Use(middleware ...PublishMiddleware) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	// Publishing reads the slice without holding the lock, so it is never modified in place.
+	eb.publishMiddleware = append(append([]PublishMiddleware{}, eb.publishMiddleware...), middleware...)
+}
+
+// UseHandler adds middleware wrapping the callbacks of all subsequently created subscriptions.
+// Middleware is applied in the order it was added, the first one being the outermost.
+func (eb *EventBus) UseHandler(middleware ...HandlerMiddleware) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	eb.handlerMiddleware = append(append([]HandlerMiddleware{}, eb.handlerMiddleware...), middleware...)
+}
+
+// intercept passes the event through the publish middleware to the publish handler.
+func (eb *EventBus) intercept(evt Event, publish PublishHandler) error {
+	eb.mu.RLock()
+	middleware := eb.publishMiddleware
+	eb.mu.RUnlock()
+
+	for i := len(middleware) - 1; i >= 0; i-- {
+		publish = middleware[i](publish)
+	}
+
+	return publish(evt)
+}
+
+// wrapHandler wraps the handler in the middleware of the EventBus and the given subscription middleware.
+func (eb *EventBus) wrapHandler(handler EventHandler, middleware []HandlerMiddleware) EventHandler {
+	eb.mu.RLock()
+	middleware = append(append([]HandlerMiddleware{}, eb.handlerMiddleware...), middleware...)
+	eb.mu.RUnlock()
+
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+
+	return handler
+}
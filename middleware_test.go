@@ -0,0 +1,136 @@
+package eventbus_test
+
+import (
+	"errors"
+	eb "github.com/dtomasi/go-event-bus/v3"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var errRejected = errors.New("rejected")
+
+func TestEventBus_Use(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	var (
+		mu    sync.Mutex
+		calls []string
+	)
+
+	record := func(name string) eb.PublishMiddleware {
+		return func(next eb.PublishHandler) eb.PublishHandler {
+			return func(evt eb.Event) error {
+				mu.Lock()
+				calls = append(calls, name)
+				mu.Unlock()
+
+				return next(evt)
+			}
+		}
+	}
+
+	// Enrich the data
+	ebi.Use(record("first"), func(next eb.PublishHandler) eb.PublishHandler {
+		return func(evt eb.Event) error {
+			if s, ok := evt.Data.(string); ok {
+				evt.Data = strings.ToUpper(s)
+			}
+
+			return next(evt)
+		}
+	})
+
+	// Reject and reroute events
+	ebi.Use(record("second"), func(next eb.PublishHandler) eb.PublishHandler {
+		return func(evt eb.Event) error {
+			switch evt.Topic {
+			case "admin":
+				return errRejected
+			case "legacy:orders":
+				evt.Topic = "orders"
+			}
+
+			return next(evt)
+		}
+	})
+
+	sub, err := ebi.Subscribe("orders", eb.WithBufferSize(2))
+	assert.NoError(t, err)
+
+	assert.NoError(t, ebi.PublishAsync("orders", "a"))
+	assert.NoError(t, ebi.PublishAsync("legacy:orders", "b"))
+	assert.ErrorIs(t, ebi.PublishAsync("admin", "c"), errRejected)
+
+	_, err = ebi.Publish("admin", "c")
+	assert.ErrorIs(t, err, errRejected)
+
+	assert.Equal(t, []interface{}{"A", "B"}, receiveData(sub, 2))
+	assert.Equal(t, []string{"first", "second", "first", "second", "first", "second", "first", "second"}, calls)
+	assert.Equal(t, 0, ebi.Stats().GetPublishedCountByTopic("admin"))
+}
+
+func TestEventBus_UseHandler(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	var calls []string
+
+	record := func(name string) eb.HandlerMiddleware {
+		return func(next eb.EventHandler) eb.EventHandler {
+			return func(evt eb.Event) error {
+				calls = append(calls, name+":before")
+				err := next(evt)
+				calls = append(calls, name+":after")
+
+				return err
+			}
+		}
+	}
+
+	ebi.UseHandler(record("bus"))
+
+	_, err := ebi.SubscribeHandler("orders", func(topic string, data interface{}) error {
+		calls = append(calls, "handler")
+
+		return nil
+	}, eb.WithMiddleware(record("sub")))
+	assert.NoError(t, err)
+
+	// Middleware can fail events without calling the handler
+	_, err = ebi.SubscribeHandler("admin", func(topic string, data interface{}) error {
+		t.Error("handler must not be called")
+
+		return nil
+	}, eb.WithMiddleware(func(next eb.EventHandler) eb.EventHandler {
+		return func(evt eb.Event) error {
+			return errRejected
+		}
+	}))
+	assert.NoError(t, err)
+
+	assert.NoError(t, ebi.PublishE("orders", "order"))
+	assert.Equal(t, []string{"bus:before", "sub:before", "handler", "sub:after", "bus:after"}, calls)
+
+	assert.ErrorIs(t, ebi.PublishE("admin", "order"), errRejected)
+}
+
+func TestEventBus_UseDropEvent(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	// Drop events without calling next
+	ebi.Use(func(next eb.PublishHandler) eb.PublishHandler {
+		return func(evt eb.Event) error {
+			return nil
+		}
+	})
+
+	sub, err := ebi.Subscribe("foo", eb.WithBufferSize(1))
+	assert.NoError(t, err)
+
+	_, err = ebi.Publish("foo", 1)
+	assert.NoError(t, err)
+	assert.NoError(t, ebi.PublishE("foo", 2))
+	assert.NoError(t, eb.NewTopic[int](ebi, "foo").Publish(3))
+	assert.Len(t, sub.Channel(), 0)
+}
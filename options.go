@@ -0,0 +1,339 @@
+package eventbus
+
+import (
+	"time"
+)
+
+// Option configures an EventBus.
+type Option func(eb *EventBus)
+
+// WithPanicHandler sets a function that is called whenever a subscriber panics.
+// By default panics are logged using the standard logger.
+func WithPanicHandler(handler PanicHandler) Option {
+	return func(eb *EventBus) {
+		eb.panicHandler = handler
+	}
+}
+
+// WithTopicMatcher sets the TopicMatcher used to match topics against subscription patterns.
+// By default a SegmentMatcher using DefaultSeparator is used.
+func WithTopicMatcher(matcher TopicMatcher) Option {
+	return func(eb *EventBus) {
+		eb.matcher = matcher
+	}
+}
+
+// WithSeparator uses a SegmentMatcher with the given topic segment separator.
+func WithSeparator(separator string) Option {
+	return WithTopicMatcher(NewSegmentMatcher(separator))
+}
+
+// WithDeadLetterTopic publishes events that failed or matched no subscription to the given topic,
+// wrapped in a DeadLetter. Events failing on the dead-letter topic itself are not routed again.
+func WithDeadLetterTopic(topic string) Option {
+	return func(eb *EventBus) {
+		eb.deadLetterTopic = topic
+	}
+}
+
+// WithTracer creates spans using the Tracer around publishing and around every handler invocation.
+// Handlers can access the span context using SpanContextFromContext on Event.Context.
+func WithTracer(tracer Tracer) Option {
+	return func(eb *EventBus) {
+		eb.tracer = tracer
+	}
+}
+
+// WithEventLog persists every published event to the EventLog before it is delivered.
+// Publishing fails if the event could not be appended. See EventBus.ReplayLog.
+func WithEventLog(log EventLog) Option {
+	return func(eb *EventBus) {
+		eb.log = log
+	}
+}
+
+// WithReplayBuffer records the events of all topics matching the pattern for replay.
+// Every topic gets its own buffer holding at most capacity events not older than maxAge.
+// A capacity or maxAge of zero means no limit, but at least one of them must be set.
+// See WithReplayLast and WithReplaySince to replay events to new subscriptions.
+func WithReplayBuffer(pattern string, capacity int, maxAge time.Duration) Option {
+	return func(eb *EventBus) {
+		if capacity > 0 || maxAge > 0 {
+			eb.replayConfigs = append(eb.replayConfigs, replayConfig{
+				pattern:  pattern,
+				capacity: capacity,
+				maxAge:   maxAge,
+			})
+		}
+	}
+}
+
+// SubscribeOption configures a Subscription.
+type SubscribeOption func(o *subscribeOptions)
+
+type subscribeOptions struct {
+	concurrency    int
+	bufferSize     int
+	overflowPolicy OverflowPolicy
+	overflowTopic  string
+	deliveryMode   DeliveryMode
+	partitions     int
+	partitionKey   KeyFunc
+	group          string
+	groupStrategy  GroupStrategy
+	replayLast     int
+	replaySince    time.Time
+	ackTimeout     time.Duration
+	maxAttempts    int
+	retryPolicy    *RetryPolicy
+	middleware     []HandlerMiddleware
+	filters        []Filter
+	priority       int
+
+	// handled is true if the events are consumed by handler workers of the EventBus.
+	handled bool
+	handler EventHandler
+}
+
+func newSubscribeOptions(opts []SubscribeOption) *subscribeOptions {
+	o := &subscribeOptions{ //nolint:exhaustivestruct
+		concurrency:    1,
+		overflowPolicy: OverflowBlock,
+		deliveryMode:   DeliveryOrdered,
+		groupStrategy:  GroupRoundRobin,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithConcurrency sets the number of workers invoking a callback concurrently.
+// By default callbacks are invoked serially by a single worker.
+func WithConcurrency(workers int) SubscribeOption {
+	return func(o *subscribeOptions) {
+		if workers > 0 {
+			o.concurrency = workers
+		}
+	}
+}
+
+// WithBufferSize sets the buffer size of channels created by the EventBus.
+// It has no effect on channels passed to SubscribeChannel.
+func WithBufferSize(size int) SubscribeOption {
+	return func(o *subscribeOptions) {
+		if size > 0 {
+			o.bufferSize = size
+		}
+	}
+}
+
+// WithOverflowPolicy sets what happens if an event is published while the subscription channel is full.
+// By default publishing blocks until the subscriber receives the event.
+func WithOverflowPolicy(policy OverflowPolicy) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.overflowPolicy = policy
+	}
+}
+
+// WithOverflowTopic publishes events dropped by the overflow policy to the given topic, wrapped in a DroppedEvent.
+func WithOverflowTopic(topic string) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.overflowTopic = topic
+	}
+}
+
+// WithDeliveryMode sets how events are delivered to subscriptions using OverflowBlock.
+// By default events are delivered in publish order using DeliveryOrdered.
+func WithDeliveryMode(mode DeliveryMode) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.deliveryMode = mode
+	}
+}
+
+// WithPartitions invokes a callback from the given number of workers, hashing the key of every event
+// to a worker. Events with the same key are handled sequentially in publish order,
+// events with different keys are handled concurrently. It takes precedence over WithConcurrency.
+func WithPartitions(partitions int, key KeyFunc) SubscribeOption {
+	return func(o *subscribeOptions) {
+		if partitions > 0 && key != nil {
+			o.partitions = partitions
+			o.partitionKey = key
+		}
+	}
+}
+
+// WithGroup makes the subscription a member of a consumer group.
+// Every event is delivered to exactly one member of the group.
+func WithGroup(group string) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.group = group
+	}
+}
+
+// WithGroupStrategy sets how the consumer group selects the member receiving an event.
+// All members of a group must use the same strategy, by default GroupRoundRobin is used.
+func WithGroupStrategy(strategy GroupStrategy) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.groupStrategy = strategy
+	}
+}
+
+// WithReplayLast delivers the last n recorded events of all matching topics before any live event.
+// Events are only recorded for topics configured using WithReplayBuffer.
+// Subscriptions using DeliveryConcurrent may receive live events before replayed events.
+func WithReplayLast(n int) SubscribeOption {
+	return func(o *subscribeOptions) {
+		if n > 0 {
+			o.replayLast = n
+		}
+	}
+}
+
+// WithReplaySince delivers all recorded events of matching topics published since the given time
+// before any live event. Events are only recorded for topics configured using WithReplayBuffer.
+// Subscriptions using DeliveryConcurrent may receive live events before replayed events.
+func WithReplaySince(since time.Time) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.replaySince = since
+	}
+}
+
+// WithAckTimeout enables at-least-once delivery. Events that are not acknowledged using Event.Done
+// within the timeout, failed or rejected using Event.Nack with requeue are redelivered
+// until maxAttempts deliveries were made. A maxAttempts of zero or less means no limit.
+// Redelivered events may be received out of publish order, see Event.Attempt.
+func WithAckTimeout(timeout time.Duration, maxAttempts int) SubscribeOption {
+	return func(o *subscribeOptions) {
+		if timeout > 0 {
+			o.ackTimeout = timeout
+			o.maxAttempts = maxAttempts
+		}
+	}
+}
+
+// WithRetryPolicy calls failing handlers and callbacks again according to the policy
+// before the event fails. Retries block the worker handling the event.
+func WithRetryPolicy(policy RetryPolicy) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.retryPolicy = &policy
+	}
+}
+
+// WithMiddleware wraps the callback of the subscription in the given middleware.
+// It is applied inside the middleware registered using EventBus.UseHandler.
+func WithMiddleware(middleware ...HandlerMiddleware) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.middleware = append(o.middleware, middleware...)
+	}
+}
+
+// WithFilter only delivers events accepted by the filter to the subscription.
+// Using WithFilter multiple times an event must be accepted by all filters.
+// Filters are evaluated while publishing and must not call the EventBus.
+// A panicking filter rejects the event, the panic is passed to the PanicHandler.
+func WithFilter(filter Filter) SubscribeOption {
+	return func(o *subscribeOptions) {
+		if filter != nil {
+			o.filters = append(o.filters, filter)
+		}
+	}
+}
+
+// WithPriority sets the priority of the subscription. Subscriptions with a higher priority
+// receive events first, subscriptions with equal priority in the order they were created.
+// The default priority is 0.
+func WithPriority(priority int) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.priority = priority
+	}
+}
+
+// replay reports whether recorded events are replayed to the subscription.
+func (o *subscribeOptions) replay() bool {
+	return o.replayLast > 0 || !o.replaySince.IsZero()
+}
+
+// newChannel returns a new EventChannel using the configured buffer size.
+func (o *subscribeOptions) newChannel() EventChannel {
+	return make(EventChannel, o.bufferSize)
+}
+
+// PublishOption configures how an event is published.
+type PublishOption func(o *publishOptions)
+
+type publishOptions struct {
+	retain  bool
+	id      string
+	source  string
+	headers map[string]string
+
+	sequential bool
+
+	// replayed is true if the event is replayed from the EventLog.
+	replayed bool
+}
+
+func newPublishOptions(opts []PublishOption) *publishOptions {
+	o := &publishOptions{} //nolint:exhaustivestruct
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithSequentialDispatch delivers a synchronously published event to one subscription after another
+// in order of priority, waiting for each to acknowledge the event before delivering it to the next.
+// A subscription may call Event.StopPropagation to skip all subscriptions with lower priority.
+// It has no effect on asynchronous publishing.
+func WithSequentialDispatch() PublishOption {
+	return func(o *publishOptions) {
+		o.sequential = true
+	}
+}
+
+// WithEventID sets the ID of the event. By default a random UUID is used.
+func WithEventID(id string) PublishOption {
+	return func(o *publishOptions) {
+		o.id = id
+	}
+}
+
+// WithSource sets the source of the event, identifying its publisher.
+func WithSource(source string) PublishOption {
+	return func(o *publishOptions) {
+		o.source = source
+	}
+}
+
+// WithHeader sets a header of the event.
+func WithHeader(key, value string) PublishOption {
+	return func(o *publishOptions) {
+		if o.headers == nil {
+			o.headers = map[string]string{}
+		}
+
+		o.headers[key] = value
+	}
+}
+
+// WithHeaders sets multiple headers of the event.
+func WithHeaders(headers map[string]string) PublishOption {
+	return func(o *publishOptions) {
+		for key, value := range headers {
+			WithHeader(key, value)(o)
+		}
+	}
+}
+
+// WithRetain stores the event as the retained event of its topic.
+// Retained events are delivered to subscriptions created later on, see EventBus.Retained.
+func WithRetain() PublishOption {
+	return func(o *publishOptions) {
+		o.retain = true
+	}
+}
@@ -0,0 +1,65 @@
+package eventbus
+
+// OverflowPolicy defines what happens if an event is published while a subscription channel is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits until the subscriber receives the event.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest drops the published event.
+	OverflowDropNewest
+	// OverflowDropOldest drops the oldest buffered event to make room for the published event.
+	OverflowDropOldest
+	// OverflowFail drops the published event and fails publishing with ErrOverflow.
+	OverflowFail
+)
+
+// DroppedEvent is published to the overflow topic of a subscription if an event was dropped.
+type DroppedEvent struct {
+	// Event that was dropped.
+	Event Event
+	// Subscription the event was dropped for.
+	Subscription *Subscription
+}
+
+// drop acknowledges an event dropped for a subscription, counts it
+// and publishes it to the overflow topic of the subscription if configured.
+func (eb *EventBus) drop(sub *Subscription, evt Event) {
+	evt.Done()
+
+	eb.stats.incDroppedCountByTopic(evt.Topic)
+
+	// Events dropped from the overflow topic itself are not routed again to prevent loops.
+	if sub.overflowTopic == "" || evt.Topic == sub.overflowTopic {
+		return
+	}
+
+	_ = eb.PublishAsync(sub.overflowTopic, DroppedEvent{
+		Event:        evt,
+		Subscription: sub,
+	})
+}
+
+// overflow handles an event that could not be delivered to a subscription without blocking.
+// It returns a *SubscriberError wrapping ErrOverflow for subscriptions using OverflowFail.
+func (eb *EventBus) overflow(sub *Subscription, evt Event) error {
+	evt.sub = sub
+
+	if sub.overflowPolicy != OverflowFail {
+		eb.drop(sub, evt)
+
+		return nil
+	}
+
+	// Overflow failures are reported to the publisher instead of the dead-letter topic.
+	if evt.acks != nil {
+		evt.acks.ack(sub.id, ErrOverflow)
+	}
+
+	eb.stats.incDroppedCountByTopic(evt.Topic)
+
+	return &SubscriberError{
+		Subscription: sub,
+		Err:          ErrOverflow,
+	}
+}
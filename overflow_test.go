@@ -0,0 +1,106 @@
+package eventbus_test
+
+import (
+	"context"
+	eb "github.com/dtomasi/go-event-bus/v3"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEventBus_SubscribeBuffered(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	sub, err := ebi.Subscribe("foo", eb.WithBufferSize(3))
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, ebi.PublishAsync("foo", i))
+	}
+
+	// All events fit into the buffer without a receiver
+	abandoned, err := ebi.Drain(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, abandoned)
+	assert.Len(t, sub.Channel(), 3)
+}
+
+func TestEventBus_OverflowDropNewest(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	sub, err := ebi.Subscribe("foo", eb.WithBufferSize(1), eb.WithOverflowPolicy(eb.OverflowDropNewest))
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, ebi.PublishAsync("foo", i))
+	}
+
+	// Synchronous publishing is not blocked by dropped events
+	_, err = ebi.Publish("foo", 3)
+	assert.NoError(t, err)
+
+	evt := <-sub.Channel()
+	assert.Equal(t, 0, evt.Data)
+	assert.Equal(t, 3, ebi.Stats().GetDroppedCountByTopic("foo"))
+}
+
+func TestEventBus_OverflowDropOldest(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	sub, err := ebi.Subscribe("foo", eb.WithBufferSize(1), eb.WithOverflowPolicy(eb.OverflowDropOldest))
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, ebi.PublishAsync("foo", i))
+	}
+
+	evt := <-sub.Channel()
+	assert.Equal(t, 2, evt.Data)
+	assert.Equal(t, 2, ebi.Stats().GetDroppedCountByTopic("foo"))
+}
+
+func TestEventBus_OverflowFail(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	sub, err := ebi.Subscribe("foo", eb.WithBufferSize(1), eb.WithOverflowPolicy(eb.OverflowFail))
+	assert.NoError(t, err)
+
+	assert.NoError(t, ebi.PublishAsync("foo", 0))
+
+	err = ebi.PublishAsync("foo", 1)
+	assert.ErrorIs(t, err, eb.ErrOverflow)
+
+	var subErr *eb.SubscriberError
+
+	assert.ErrorAs(t, err, &subErr)
+	assert.Equal(t, sub.ID(), subErr.Subscription.ID())
+
+	_, err = ebi.Publish("foo", 2)
+	assert.ErrorIs(t, err, eb.ErrOverflow)
+
+	assert.Equal(t, 2, ebi.Stats().GetDroppedCountByTopic("foo"))
+}
+
+func TestEventBus_OverflowTopic(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	dropped, err := ebi.Subscribe("dropped", eb.WithBufferSize(10))
+	assert.NoError(t, err)
+
+	sub, err := ebi.Subscribe(
+		"foo",
+		eb.WithBufferSize(1),
+		eb.WithOverflowPolicy(eb.OverflowDropNewest),
+		eb.WithOverflowTopic("dropped"),
+	)
+	assert.NoError(t, err)
+
+	assert.NoError(t, ebi.PublishAsync("foo", 0))
+	assert.NoError(t, ebi.PublishAsync("foo", 1))
+
+	evt := <-dropped.Channel()
+	droppedEvent, ok := evt.Data.(eb.DroppedEvent)
+	assert.True(t, ok)
+	assert.Equal(t, 1, droppedEvent.Event.Data)
+	assert.Equal(t, "foo", droppedEvent.Event.Topic)
+	assert.Equal(t, sub.ID(), droppedEvent.Subscription.ID())
+}
@@ -0,0 +1,44 @@
+package eventbus
+
+import (
+	"fmt"
+	"log"
+	"runtime/debug"
+)
+
+// PanicHandler is called with the recovered panic of a subscriber.
+type PanicHandler func(sub *Subscription, evt Event, err *PanicError)
+
+// PanicError is a recovered panic of a subscriber.
+type PanicError struct {
+	// Value passed to panic.
+	Value interface{}
+	// Stack trace of the panicking goroutine.
+	Stack []byte
+}
+
+func newPanicError(value interface{}) *PanicError {
+	return &PanicError{
+		Value: value,
+		Stack: debug.Stack(),
+	}
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("subscriber panicked: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+
+	return nil
+}
+
+// logPanic is the default PanicHandler.
+func logPanic(sub *Subscription, evt Event, err *PanicError) {
+	log.Printf("eventbus: subscription %d (%s) panicked on topic %q: %v\n%s",
+		sub.ID(), sub.Topic(), evt.Topic, err.Value, err.Stack)
+}
@@ -0,0 +1,52 @@
+package eventbus_test
+
+import (
+	eb "github.com/dtomasi/go-event-bus/v3"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEventBus_SubscriberPanic(t *testing.T) {
+	const testTopicName = "foo:bar"
+
+	var handled []*eb.PanicError
+
+	ebi := eb.NewEventBus(eb.WithPanicHandler(func(sub *eb.Subscription, evt eb.Event, err *eb.PanicError) {
+		assert.Equal(t, testTopicName, evt.Topic)
+		handled = append(handled, err)
+	}))
+
+	callCounter := eb.NewSafeCounter()
+
+	sub, err := ebi.SubscribeCallback(testTopicName, func(topic string, data interface{}) {
+		callCounter.Inc()
+
+		if data == "panic" {
+			panic("boom")
+		}
+	})
+	assert.NoError(t, err)
+
+	// Publish is released although the subscriber panics
+	_, err = ebi.Publish(testTopicName, "panic")
+	assert.NoError(t, err)
+
+	err = ebi.PublishE(testTopicName, "panic")
+
+	var panicErr *eb.PanicError
+
+	assert.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "boom", panicErr.Value)
+	assert.NotEmpty(t, panicErr.Stack)
+
+	var subErr *eb.SubscriberError
+
+	assert.ErrorAs(t, err, &subErr)
+	assert.Equal(t, sub.ID(), subErr.Subscription.ID())
+
+	// The subscriber keeps working after a panic
+	assert.NoError(t, ebi.PublishE(testTopicName, "bar"))
+	assert.Equal(t, 3, callCounter.Value())
+
+	assert.Len(t, handled, 2)
+}
@@ -0,0 +1,119 @@
+package eventbus
+
+import (
+	"hash/fnv"
+	"sync"
+)
+
+// KeyFunc extracts the partition key from the data of an event.
+type KeyFunc func(data interface{}) string
+
+// partition is a single worker queue of a partitioned subscription.
+type partition struct {
+	queue *eventQueue
+	depth *SafeCounter
+}
+
+// runPartitioned distributes the events of the subscription to partition workers by key.
+// Events with the same key are handled sequentially by the same worker, different keys are handled concurrently.
+func (eb *EventBus) runPartitioned(sub *Subscription, handler EventHandler, key KeyFunc, pStats *PartitionStats) {
+	var wg sync.WaitGroup
+
+	partitions := make([]*partition, len(pStats.QueueDepth))
+
+	for i := range partitions {
+		partitions[i] = &partition{
+			queue: newEventQueue(),
+			depth: pStats.QueueDepth[i],
+		}
+
+		wg.Add(1)
+
+		go func(p *partition) {
+			defer wg.Done()
+
+			eb.runPartition(sub, handler, p)
+		}(partitions[i])
+	}
+
+	defer func() {
+		wg.Wait()
+
+		// Release events that were not handled anymore.
+		for _, p := range partitions {
+			for item, ok := p.queue.pop(); ok; item, ok = p.queue.pop() {
+				p.depth.Dec()
+				item.evt.Done()
+			}
+		}
+	}()
+
+	for {
+		select {
+		case evt, ok := <-sub.ch:
+			if !ok {
+				return
+			}
+
+			k, err := eb.partitionKey(sub, key, evt)
+			if err != nil {
+				evt.Fail(err)
+
+				continue
+			}
+
+			p := partitions[partitionIndex(k, len(partitions))]
+			p.depth.Inc()
+			p.queue.push(queuedEvent{
+				evt:     evt,
+				release: func() {},
+			})
+		case <-sub.done:
+			return
+		}
+	}
+}
+
+// partitionKey extracts the partition key of the event.
+// Panics are recovered, passed to the PanicHandler and reported as *PanicError.
+func (eb *EventBus) partitionKey(sub *Subscription, key KeyFunc, evt Event) (k string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := newPanicError(r)
+			err = panicErr
+
+			if eb.panicHandler != nil {
+				eb.panicHandler(sub, evt, panicErr)
+			}
+		}
+	}()
+
+	return key(evt.Data), nil
+}
+
+// runPartition invokes the handler for every event in the partition queue until the subscription is stopped.
+func (eb *EventBus) runPartition(sub *Subscription, handler EventHandler, p *partition) {
+	for {
+		item, ok := p.queue.pop()
+		if ok {
+			p.depth.Dec()
+			eb.invokeHandler(sub, handler, item.evt)
+
+			continue
+		}
+
+		select {
+		case <-p.queue.notify:
+		case <-sub.done:
+			return
+		}
+	}
+}
+
+// partitionIndex hashes the key to one of count partitions.
+func partitionIndex(key string, count int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+
+	return int(h.Sum32() % uint32(count))
+}
@@ -0,0 +1,134 @@
+package eventbus_test
+
+import (
+	eb "github.com/dtomasi/go-event-bus/v3"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+type partitionedPayload struct {
+	Key   string
+	Value int
+}
+
+func partitionKey(data interface{}) string {
+	return data.(partitionedPayload).Key
+}
+
+func TestEventBus_SubscribePartitioned(t *testing.T) {
+	const count = 100
+
+	ebi := eb.NewEventBus()
+
+	var (
+		mu       sync.Mutex
+		received = map[string][]int{}
+	)
+
+	_, err := ebi.SubscribeCallback("foo", func(topic string, data interface{}) {
+		payload := data.(partitionedPayload)
+
+		mu.Lock()
+		received[payload.Key] = append(received[payload.Key], payload.Value)
+		mu.Unlock()
+	}, eb.WithPartitions(4, partitionKey))
+	assert.NoError(t, err)
+
+	keys := []string{"a", "b", "c", "d", "e"}
+
+	for i := 0; i < count; i++ {
+		for _, key := range keys {
+			assert.NoError(t, ebi.PublishAsync("foo", partitionedPayload{Key: key, Value: i}))
+		}
+	}
+
+	// Synchronous publishing waits for the partitioned handler
+	for _, key := range keys {
+		_, err = ebi.Publish("foo", partitionedPayload{Key: key, Value: count})
+		assert.NoError(t, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// Events with the same key are handled in publish order
+	for _, key := range keys {
+		assert.Len(t, received[key], count+1, key)
+
+		for i, value := range received[key] {
+			assert.Equal(t, i, value, key)
+		}
+	}
+}
+
+func TestEventBus_PartitionStats(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	release := make(chan struct{})
+
+	sub, err := ebi.SubscribeCallback("foo", func(topic string, data interface{}) {
+		<-release
+	}, eb.WithPartitions(2, partitionKey))
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, ebi.PublishAsync("foo", partitionedPayload{Key: "a", Value: i}))
+	}
+
+	pStats := ebi.Stats().GetPartitionStatsBySubscription(sub.ID())
+	assert.NotNil(t, pStats)
+	assert.Len(t, pStats.QueueDepth, 2)
+
+	queueDepth := func() int {
+		depth := 0
+		for _, counter := range pStats.QueueDepth {
+			depth += counter.Value()
+		}
+
+		return depth
+	}
+
+	// One event is handled, the others wait in the queue of the same partition
+	assert.Eventually(t, func() bool {
+		return queueDepth() == 2
+	}, time.Second, time.Millisecond)
+
+	close(release)
+
+	assert.Eventually(t, func() bool {
+		return queueDepth() == 0
+	}, time.Second, time.Millisecond)
+
+	sub.Unsubscribe()
+
+	assert.Nil(t, ebi.Stats().GetPartitionStatsBySubscription(sub.ID()))
+}
+
+func TestEventBus_PartitionKeyPanic(t *testing.T) {
+	panics := make(chan *eb.PanicError, 1)
+
+	ebi := eb.NewEventBus(eb.WithPanicHandler(func(sub *eb.Subscription, evt eb.Event, err *eb.PanicError) {
+		panics <- err
+	}))
+
+	var received []partitionedPayload
+
+	_, err := ebi.SubscribeCallback("foo", func(topic string, data interface{}) {
+		received = append(received, data.(partitionedPayload))
+	}, eb.WithPartitions(2, partitionKey))
+	assert.NoError(t, err)
+
+	// The key extractor can not handle the payload
+	err = ebi.PublishE("foo", 1)
+
+	var panicErr *eb.PanicError
+
+	assert.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, panicErr, <-panics)
+
+	// The subscription keeps consuming events
+	assert.NoError(t, ebi.PublishE("foo", partitionedPayload{Key: "a", Value: 1}))
+	assert.Equal(t, []partitionedPayload{{Key: "a", Value: 1}}, received)
+}
@@ -0,0 +1,64 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+)
+
+// pendingTracker keeps track of events that are not yet delivered to all subscriptions.
+type pendingTracker struct {
+	mu    sync.Mutex
+	count int
+	idle  chan struct{}
+}
+
+func newPendingTracker() *pendingTracker {
+	idle := make(chan struct{})
+	close(idle)
+
+	return &pendingTracker{ //nolint:exhaustivestruct
+		idle: idle,
+	}
+}
+
+// add registers a pending event.
+func (p *pendingTracker) add() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.count == 0 {
+		p.idle = make(chan struct{})
+	}
+
+	p.count++
+}
+
+// done marks a pending event as delivered.
+func (p *pendingTracker) done() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.count--
+
+	if p.count == 0 {
+		close(p.idle)
+	}
+}
+
+// wait blocks until no events are pending or the context expires.
+// It returns the number of events still pending when the context expired.
+func (p *pendingTracker) wait(ctx context.Context) (int, error) {
+	p.mu.Lock()
+	idle := p.idle
+	p.mu.Unlock()
+
+	select {
+	case <-idle:
+		return 0, nil
+	case <-ctx.Done():
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		return p.count, ctx.Err()
+	}
+}
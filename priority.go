@@ -0,0 +1,73 @@
+package eventbus
+
+import (
+	"sort"
+	"sync/atomic"
+)
+
+// propagation is shared by all copies of an event dispatched sequentially.
+type propagation struct {
+	stopped int32
+}
+
+// StopPropagation skips all subscriptions with a lower priority than the current one
+// if the event was published using WithSequentialDispatch. Otherwise it has no effect.
+func (e *Event) StopPropagation() {
+	if e.propagation != nil {
+		atomic.StoreInt32(&e.propagation.stopped, 1)
+	}
+}
+
+// isStopped reports whether a subscription stopped the propagation of the event.
+func (p *propagation) isStopped() bool {
+	return atomic.LoadInt32(&p.stopped) == 1
+}
+
+// sortByPriority orders subscriptions by descending priority, subscriptions with equal priority by creation.
+func sortByPriority(subs subscriptionSlice) {
+	sort.Slice(subs, func(i, j int) bool {
+		if subs[i].priority != subs[j].priority {
+			return subs[i].priority > subs[j].priority
+		}
+
+		return subs[i].id < subs[j].id
+	})
+}
+
+// dispatchSequential hands the event over to one subscription after another and waits for each to
+// acknowledge it, until a subscription stops the propagation. Skipped subscriptions are acknowledged
+// without error. The pending event registered by prepareSubscriptions is released when done.
+func (eb *EventBus) dispatchSequential(subs subscriptionSlice, evt Event) (*ackTracker, error) {
+	defer eb.pending.done()
+
+	acks := newAckTracker(subs)
+	evt.propagation = &propagation{} //nolint:exhaustivestruct
+
+	for _, sub := range subs {
+		if evt.propagation.isStopped() {
+			acks.ack(sub.id, nil)
+
+			continue
+		}
+
+		step := evt
+		step.acks = newAckTracker(subscriptionSlice{sub})
+
+		eb.pending.add()
+		_ = eb.doPublish(subscriptionSlice{sub}, step)
+
+		// On expiry of the context the current and all remaining subscriptions are reported.
+		if err := step.acks.wait(evt.Context(), evt.Topic); err != nil {
+			return acks, acks.wait(evt.Context(), evt.Topic)
+		}
+
+		var err error
+		if failures := step.acks.errors(); len(failures) > 0 {
+			err = failures[0].Err
+		}
+
+		acks.ack(sub.id, err)
+	}
+
+	return acks, nil
+}
@@ -0,0 +1,133 @@
+package eventbus_test
+
+import (
+	"context"
+	eb "github.com/dtomasi/go-event-bus/v3"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestEventBus_WithPriority(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	var order []string
+
+	subscribe := func(name string, opts ...eb.SubscribeOption) *eb.Subscription {
+		sub, err := ebi.SubscribeCallback("orders:*", func(topic string, data interface{}) {
+			order = append(order, name)
+		}, opts...)
+		assert.NoError(t, err)
+
+		return sub
+	}
+
+	subscribe("default")
+	subscribe("low", eb.WithPriority(-5))
+	high := subscribe("high", eb.WithPriority(10))
+	subscribe("medium", eb.WithPriority(5))
+	subscribe("default 2")
+
+	assert.Equal(t, 10, high.Priority())
+
+	_, err := ebi.Publish("orders:created", "order", eb.WithSequentialDispatch())
+	assert.NoError(t, err)
+
+	// Equal priorities are served in the order the subscriptions were created
+	assert.Equal(t, []string{"high", "medium", "default", "default 2", "low"}, order)
+}
+
+func TestEventBus_StopPropagation(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	var order []string
+
+	_, err := ebi.SubscribeEvent("orders", func(evt eb.Event) error {
+		order = append(order, "audit")
+
+		return nil
+	}, eb.WithPriority(20))
+	assert.NoError(t, err)
+
+	_, err = ebi.SubscribeEvent("orders", func(evt eb.Event) error {
+		order = append(order, "validate")
+
+		if evt.Data == "invalid" {
+			evt.StopPropagation()
+		}
+
+		return nil
+	}, eb.WithPriority(10))
+	assert.NoError(t, err)
+
+	_, err = ebi.SubscribeEvent("orders", func(evt eb.Event) error {
+		order = append(order, "process")
+
+		return nil
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, ebi.PublishE("orders", "invalid", eb.WithSequentialDispatch()))
+	assert.Equal(t, []string{"audit", "validate"}, order)
+
+	order = nil
+
+	assert.NoError(t, ebi.PublishE("orders", "valid", eb.WithSequentialDispatch()))
+	assert.Equal(t, []string{"audit", "validate", "process"}, order)
+}
+
+func TestEventBus_SequentialDispatchError(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	calls := 0
+
+	failing, err := ebi.SubscribeHandler("orders", func(topic string, data interface{}) error {
+		calls++
+
+		return errTest
+	}, eb.WithPriority(1))
+	assert.NoError(t, err)
+
+	_, err = ebi.SubscribeHandler("orders", func(topic string, data interface{}) error {
+		calls++
+
+		return nil
+	})
+	assert.NoError(t, err)
+
+	// Failures do not stop the propagation
+	err = ebi.PublishE("orders", "order", eb.WithSequentialDispatch())
+
+	var subErr *eb.SubscriberError
+
+	assert.ErrorIs(t, err, errTest)
+	assert.ErrorAs(t, err, &subErr)
+	assert.Equal(t, failing, subErr.Subscription)
+	assert.Equal(t, 2, calls)
+}
+
+func TestEventBus_SequentialDispatchTimeout(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	// Subscriber that never calls Done
+	lazy, err := ebi.Subscribe("orders", eb.WithPriority(1), eb.WithBufferSize(1))
+	assert.NoError(t, err)
+
+	next, err := ebi.Subscribe("orders", eb.WithBufferSize(1))
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err = ebi.PublishCtx(ctx, "orders", "order", eb.WithSequentialDispatch())
+
+	var ackErr *eb.AckError
+
+	assert.ErrorAs(t, err, &ackErr)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, []*eb.Subscription{lazy, next}, ackErr.Subscriptions)
+
+	// The event is not delivered to subscriptions after the one that did not acknowledge it
+	assert.Len(t, lazy.Channel(), 1)
+	assert.Len(t, next.Channel(), 0)
+}
@@ -0,0 +1,85 @@
+package eventbus
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// DeliveryMode defines how events are delivered to a subscription using OverflowBlock.
+type DeliveryMode int
+
+const (
+	// DeliveryOrdered queues events per subscription and delivers them by a dispatcher goroutine
+	// in the order they were published.
+	DeliveryOrdered DeliveryMode = iota
+	// DeliveryConcurrent delivers every published event from its own goroutine.
+	// Events published in sequence may arrive out of order.
+	DeliveryConcurrent
+)
+
+// queuedEvent is an event waiting in the queue of a subscription.
+type queuedEvent struct {
+	evt Event
+	// release is called once the event was handed over to the subscription.
+	release func()
+}
+
+// eventQueue is an unbounded FIFO queue of events.
+type eventQueue struct {
+	mu     sync.Mutex
+	items  []queuedEvent
+	notify chan struct{}
+}
+
+func newEventQueue() *eventQueue {
+	return &eventQueue{ //nolint:exhaustivestruct
+		notify: make(chan struct{}, 1),
+	}
+}
+
+// push appends an event and wakes up the dispatcher.
+func (q *eventQueue) push(item queuedEvent) {
+	q.mu.Lock()
+	q.items = append(q.items, item)
+	q.mu.Unlock()
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+// pop removes the first event of the queue.
+func (q *eventQueue) pop() (queuedEvent, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.items) == 0 {
+		return queuedEvent{}, false //nolint:exhaustivestruct
+	}
+
+	item := q.items[0]
+	q.items[0] = queuedEvent{} //nolint:exhaustivestruct
+	q.items = q.items[1:]
+
+	return item, true
+}
+
+// len returns the number of queued events.
+func (q *eventQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.items)
+}
+
+// newRelease returns a function that calls done after it was called count times.
+func newRelease(count int, done func()) func() {
+	remaining := int32(count)
+
+	return func() {
+		if atomic.AddInt32(&remaining, -1) == 0 {
+			done()
+		}
+	}
+}
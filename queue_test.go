@@ -0,0 +1,68 @@
+package eventbus_test
+
+import (
+	"context"
+	eb "github.com/dtomasi/go-event-bus/v3"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestEventBus_DeliveryOrdered(t *testing.T) {
+	const count = 1000
+
+	ebi := eb.NewEventBus()
+
+	sub, err := ebi.Subscribe("foo")
+	assert.NoError(t, err)
+
+	for i := 0; i < count; i++ {
+		assert.NoError(t, ebi.PublishAsync("foo", i))
+	}
+
+	for i := 0; i < count; i++ {
+		evt := <-sub.Channel()
+		assert.Equal(t, i, evt.Data)
+	}
+}
+
+func TestEventBus_DeliveryConcurrent(t *testing.T) {
+	const count = 100
+
+	ebi := eb.NewEventBus()
+
+	sub, err := ebi.Subscribe("foo", eb.WithDeliveryMode(eb.DeliveryConcurrent))
+	assert.NoError(t, err)
+
+	for i := 0; i < count; i++ {
+		assert.NoError(t, ebi.PublishAsync("foo", i))
+	}
+
+	var received []interface{}
+	for i := 0; i < count; i++ {
+		received = append(received, (<-sub.Channel()).Data)
+	}
+
+	assert.Len(t, received, count)
+}
+
+func TestEventBus_DeliveryOrderedUnsubscribe(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	sub, err := ebi.Subscribe("foo")
+	assert.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, ebi.PublishAsync("foo", i))
+	}
+
+	// Queued events are released on unsubscribe
+	sub.Unsubscribe()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	abandoned, err := ebi.Drain(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, abandoned)
+}
@@ -0,0 +1,104 @@
+package eventbus
+
+import (
+	"sync"
+	"time"
+)
+
+// delivery tracks an event delivered to a subscription using WithAckTimeout until it is settled.
+type delivery struct {
+	sub *Subscription
+	evt Event
+
+	mu      sync.Mutex
+	attempt int
+	retries int
+	settled bool
+	timer   *time.Timer
+}
+
+// track attaches a delivery to the event if the subscription requires acknowledgements.
+// Events that are redelivered keep their delivery.
+func (s *Subscription) track(evt Event) Event {
+	if s.ackTimeout <= 0 || evt.delivery != nil {
+		return evt
+	}
+
+	d := &delivery{ //nolint:exhaustivestruct
+		sub:     s,
+		evt:     evt,
+		attempt: 1,
+	}
+
+	evt.delivery = d
+	evt.attempt = 1
+
+	return evt
+}
+
+// arm starts the acknowledgement timeout of an attempt once the event was handed over.
+func (d *delivery) arm(attempt int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.settled || attempt != d.attempt {
+		return
+	}
+
+	d.timer = time.AfterFunc(d.sub.ackTimeout, func() {
+		d.settle(attempt, 0, ErrAckTimeout, true)
+	})
+}
+
+// settle finishes an attempt after retries retries of the handler. If requeue is true and attempts are left
+// the event is redelivered, otherwise the event is acknowledged reporting err. Settling an outdated attempt is a no-op.
+func (d *delivery) settle(attempt, retries int, err error, requeue bool) {
+	d.mu.Lock()
+
+	if d.settled || attempt != d.attempt {
+		d.mu.Unlock()
+
+		return
+	}
+
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+
+	d.retries += retries
+
+	if requeue && (d.sub.maxAttempts <= 0 || d.attempt < d.sub.maxAttempts) {
+		d.attempt++
+		next := d.attempt
+		d.mu.Unlock()
+
+		d.redeliver(next)
+
+		return
+	}
+
+	d.settled = true
+	d.mu.Unlock()
+
+	evt := d.evt
+	evt.attempt = d.attempt
+	evt.retries = d.retries
+	evt.Fail(err)
+}
+
+// redeliver hands the event over to the subscription again.
+// Redelivered events are not queued, so they may be received out of publish order.
+func (d *delivery) redeliver(attempt int) {
+	evt := d.evt
+	evt.delivery = d
+	evt.attempt = attempt
+
+	pending := d.sub.bus.pending
+	pending.add()
+
+	go func() {
+		defer pending.done()
+
+		d.sub.deliver(evt)
+	}()
+}
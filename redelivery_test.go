@@ -0,0 +1,115 @@
+package eventbus_test
+
+import (
+	eb "github.com/dtomasi/go-event-bus/v3"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestEventBus_AckTimeoutRedelivery(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	sub, err := ebi.Subscribe("orders", eb.WithAckTimeout(10*time.Millisecond, 3))
+	assert.NoError(t, err)
+
+	assert.NoError(t, ebi.PublishAsync("orders", "order"))
+
+	// The first delivery is not acknowledged and therefore redelivered
+	evt := <-sub.Channel()
+	assert.Equal(t, 1, evt.Attempt())
+
+	evt = <-sub.Channel()
+	assert.Equal(t, 2, evt.Attempt())
+	assert.Equal(t, "order", evt.Data)
+
+	evt.Done()
+
+	select {
+	case evt = <-sub.Channel():
+		t.Errorf("unexpected redelivery of attempt %d", evt.Attempt())
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestEventBus_AckTimeoutMaxAttempts(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	sub, err := ebi.Subscribe("orders", eb.WithAckTimeout(5*time.Millisecond, 2))
+	assert.NoError(t, err)
+
+	attempts := make(chan int, 3)
+
+	go func() {
+		for evt := range sub.Channel() {
+			attempts <- evt.Attempt()
+		}
+	}()
+
+	// The event fails once all attempts timed out
+	err = ebi.PublishE("orders", "order")
+	assert.ErrorIs(t, err, eb.ErrAckTimeout)
+
+	assert.Equal(t, 1, <-attempts)
+	assert.Equal(t, 2, <-attempts)
+	assert.Len(t, attempts, 0)
+}
+
+func TestEventBus_Nack(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	sub, err := ebi.Subscribe("orders", eb.WithAckTimeout(time.Minute, 0))
+	assert.NoError(t, err)
+
+	var attempts []int
+
+	go func() {
+		for evt := range sub.Channel() {
+			attempts = append(attempts, evt.Attempt())
+
+			// Requeue twice, then reject for good
+			evt.Nack(evt.Attempt() < 3)
+		}
+	}()
+
+	err = ebi.PublishE("orders", "order")
+	assert.ErrorIs(t, err, eb.ErrNacked)
+	assert.Equal(t, []int{1, 2, 3}, attempts)
+}
+
+func TestEventBus_AckTimeoutHandler(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	calls := eb.NewSafeCounter()
+
+	// Failed events are redelivered to the handler until it succeeds
+	_, err := ebi.SubscribeHandler("orders", func(topic string, data interface{}) error {
+		calls.Inc()
+
+		if calls.Value() < 3 {
+			return errTest
+		}
+
+		return nil
+	}, eb.WithAckTimeout(time.Minute, 5))
+	assert.NoError(t, err)
+
+	assert.NoError(t, ebi.PublishE("orders", "order"))
+	assert.Equal(t, 3, calls.Value())
+}
+
+func TestEvent_NackWithoutAckTimeout(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	sub, err := ebi.Subscribe("orders")
+	assert.NoError(t, err)
+
+	go func() {
+		evt := <-sub.Channel()
+		assert.Equal(t, 1, evt.Attempt())
+
+		evt.Nack(true)
+	}()
+
+	assert.ErrorIs(t, ebi.PublishE("orders", "order"), eb.ErrNacked)
+}
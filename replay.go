@@ -0,0 +1,134 @@
+package eventbus
+
+import (
+	"sort"
+	"time"
+)
+
+// replayConfig configures the replay buffers of all topics matching a pattern.
+type replayConfig struct {
+	pattern  string
+	capacity int
+	maxAge   time.Duration
+}
+
+// replayEntry is an event recorded for replay.
+type replayEntry struct {
+	seq uint64
+	at  time.Time
+	evt Event
+}
+
+// replayBuffer holds the most recent events of a topic, bounded by count and age.
+type replayBuffer struct {
+	capacity int
+	maxAge   time.Duration
+	entries  []replayEntry
+}
+
+// add appends an entry and evicts entries exceeding the capacity or the maximum age.
+func (b *replayBuffer) add(entry replayEntry) {
+	b.entries = append(b.entries, entry)
+
+	if b.capacity > 0 && len(b.entries) > b.capacity {
+		b.entries = b.entries[len(b.entries)-b.capacity:]
+	}
+
+	b.expire(entry.at)
+}
+
+// expire evicts all entries older than the maximum age.
+func (b *replayBuffer) expire(now time.Time) {
+	if b.maxAge <= 0 {
+		return
+	}
+
+	expired := 0
+	for expired < len(b.entries) && now.Sub(b.entries[expired].at) > b.maxAge {
+		expired++
+	}
+
+	b.entries = b.entries[expired:]
+}
+
+// record stores the event in the replay buffer of its topic if the topic is configured for replay.
+// The caller must hold the read lock, so recording does not interleave with new subscriptions.
+func (eb *EventBus) record(evt Event) {
+	cfg, ok := eb.replayConfig(evt.Topic)
+	if !ok {
+		return
+	}
+
+	eb.replayMu.Lock()
+	defer eb.replayMu.Unlock()
+
+	buf, ok := eb.replays[evt.Topic]
+	if !ok {
+		buf = &replayBuffer{ //nolint:exhaustivestruct
+			capacity: cfg.capacity,
+			maxAge:   cfg.maxAge,
+		}
+		eb.replays[evt.Topic] = buf
+	}
+
+	eb.replaySeq++
+
+	buf.add(replayEntry{
+		seq: eb.replaySeq,
+		at:  time.Now(),
+		evt: evt.detached(),
+	})
+}
+
+// replayConfig returns the first replay configuration whose pattern matches the topic.
+func (eb *EventBus) replayConfig(topic string) (replayConfig, bool) {
+	for _, cfg := range eb.replayConfigs {
+		if cfg.pattern == topic || eb.matcher.Match(cfg.pattern, topic) {
+			return cfg, true
+		}
+	}
+
+	return replayConfig{}, false //nolint:exhaustivestruct
+}
+
+// replay returns the recorded events of all topics matching the pattern in publish order,
+// limited by the replay options of the subscription.
+func (eb *EventBus) replay(pattern string, o *subscribeOptions) []Event {
+	eb.replayMu.Lock()
+	defer eb.replayMu.Unlock()
+
+	now := time.Now()
+
+	var entries []replayEntry
+
+	for topic, buf := range eb.replays {
+		if pattern != topic && !eb.matcher.Match(pattern, topic) {
+			continue
+		}
+
+		buf.expire(now)
+
+		for _, entry := range buf.entries {
+			if entry.at.Before(o.replaySince) {
+				continue
+			}
+
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].seq < entries[j].seq
+	})
+
+	if o.replayLast > 0 && len(entries) > o.replayLast {
+		entries = entries[len(entries)-o.replayLast:]
+	}
+
+	events := make([]Event, 0, len(entries))
+	for _, entry := range entries {
+		events = append(events, entry.evt)
+	}
+
+	return events
+}
@@ -0,0 +1,182 @@
+package eventbus_test
+
+import (
+	eb "github.com/dtomasi/go-event-bus/v3"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func receiveData(sub *eb.Subscription, n int) []interface{} {
+	var received []interface{}
+	for i := 0; i < n; i++ {
+		received = append(received, (<-sub.Channel()).Data)
+	}
+
+	return received
+}
+
+func TestEventBus_ReplayLast(t *testing.T) {
+	ebi := eb.NewEventBus(eb.WithReplayBuffer("orders:#", 3, 0))
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, ebi.PublishAsync("orders:created", i))
+	}
+
+	// Topics without a replay buffer are not recorded
+	assert.NoError(t, ebi.PublishAsync("users:created", "user"))
+
+	sub, err := ebi.Subscribe("orders:created", eb.WithReplayLast(10))
+	assert.NoError(t, err)
+
+	assert.NoError(t, ebi.PublishAsync("orders:created", 5))
+
+	// The buffer holds the last 3 events, followed by live events
+	assert.Equal(t, []interface{}{2, 3, 4, 5}, receiveData(sub, 4))
+
+	users, err := ebi.Subscribe("users:created", eb.WithReplayLast(10), eb.WithBufferSize(1))
+	assert.NoError(t, err)
+	assert.Len(t, users.Channel(), 0)
+}
+
+func TestEventBus_ReplayWildcard(t *testing.T) {
+	ebi := eb.NewEventBus(eb.WithReplayBuffer("#", 10, 0))
+
+	assert.NoError(t, ebi.PublishAsync("orders:created", "a"))
+	assert.NoError(t, ebi.PublishAsync("orders:deleted", "b"))
+	assert.NoError(t, ebi.PublishAsync("orders:created", "c"))
+	assert.NoError(t, ebi.PublishAsync("users:created", "d"))
+
+	// Events of all matching topics are replayed in publish order
+	sub, err := ebi.Subscribe("orders:*", eb.WithReplayLast(2))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []interface{}{"b", "c"}, receiveData(sub, 2))
+}
+
+func TestEventBus_ReplaySince(t *testing.T) {
+	ebi := eb.NewEventBus(eb.WithReplayBuffer("orders", 0, time.Hour))
+
+	assert.NoError(t, ebi.PublishAsync("orders", "old"))
+
+	time.Sleep(10 * time.Millisecond)
+
+	since := time.Now()
+
+	assert.NoError(t, ebi.PublishAsync("orders", "new"))
+
+	sub, err := ebi.Subscribe("orders", eb.WithReplaySince(since))
+	assert.NoError(t, err)
+
+	assert.NoError(t, ebi.PublishAsync("orders", "live"))
+
+	assert.Equal(t, []interface{}{"new", "live"}, receiveData(sub, 2))
+}
+
+func TestEventBus_ReplayMaxAge(t *testing.T) {
+	ebi := eb.NewEventBus(eb.WithReplayBuffer("orders", 0, 10*time.Millisecond))
+
+	assert.NoError(t, ebi.PublishAsync("orders", "expired"))
+
+	time.Sleep(20 * time.Millisecond)
+
+	assert.NoError(t, ebi.PublishAsync("orders", "recent"))
+
+	sub, err := ebi.Subscribe("orders", eb.WithReplayLast(10))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []interface{}{"recent"}, receiveData(sub, 1))
+}
+
+func TestEventBus_ReplayExactlyOnce(t *testing.T) {
+	const events = 1000
+
+	ebi := eb.NewEventBus(eb.WithReplayBuffer("counter", events, 0))
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for i := 0; i < events; i++ {
+			assert.NoError(t, ebi.PublishAsync("counter", i))
+		}
+	}()
+
+	// Subscribing while publishing must neither lose nor duplicate events
+	sub, err := ebi.Subscribe("counter", eb.WithReplayLast(events))
+	assert.NoError(t, err)
+
+	<-done
+
+	for i := 0; i < events; i++ {
+		evt := <-sub.Channel()
+		assert.Equal(t, i, evt.Data)
+	}
+}
+
+func TestEventBus_ReplayBeforeLiveNonBlocking(t *testing.T) {
+	const events = 1000
+
+	ebi := eb.NewEventBus(eb.WithReplayBuffer("counter", events, 0))
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for i := 0; i < events; i++ {
+			assert.NoError(t, ebi.PublishAsync("counter", i))
+		}
+	}()
+
+	// Replayed events are received before live events published while subscribing
+	sub, err := ebi.Subscribe("counter",
+		eb.WithReplayLast(events),
+		eb.WithBufferSize(events),
+		eb.WithOverflowPolicy(eb.OverflowDropNewest),
+	)
+	assert.NoError(t, err)
+
+	<-done
+
+	for i := 0; i < events; i++ {
+		evt := <-sub.Channel()
+		assert.Equal(t, i, evt.Data)
+	}
+}
+
+func TestEventBus_ReplayConcurrent(t *testing.T) {
+	const events = 1000
+
+	ebi := eb.NewEventBus(eb.WithReplayBuffer("counter", events, 0))
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for i := 0; i < events; i++ {
+			assert.NoError(t, ebi.PublishAsync("counter", i))
+		}
+	}()
+
+	// Live events may overtake replayed events, but every event is received exactly once
+	sub, err := ebi.Subscribe("counter",
+		eb.WithReplayLast(events),
+		eb.WithDeliveryMode(eb.DeliveryConcurrent),
+	)
+	assert.NoError(t, err)
+
+	<-done
+
+	received := receiveData(sub, events)
+	assert.Len(t, sub.Channel(), 0)
+
+	expected := make([]interface{}, events)
+	for i := range expected {
+		expected[i] = i
+	}
+
+	assert.ElementsMatch(t, expected, received)
+}
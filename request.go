@@ -0,0 +1,119 @@
+package eventbus
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+)
+
+// inboxPrefix is the prefix of the unique inboxes replies are routed to.
+const inboxPrefix = "_INBOX:"
+
+// Request publishes data to a topic and waits for the first reply sent using Event.Respond.
+// It returns ErrNoResponders if no subscriber is listening on the topic
+// and the context error if the context expires before a reply was received.
+func (eb *EventBus) Request(ctx context.Context, topic string, data interface{}) (interface{}, error) {
+	replies, err := eb.RequestMany(ctx, topic, data, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return replies[0], nil
+}
+
+// RequestMany publishes data to a topic and gathers replies sent using Event.Respond.
+// It returns as soon as maxReplies replies were received or the context expires.
+// If maxReplies is zero or less replies are gathered until the context expires.
+// The context error is only returned if no reply was received at all.
+func (eb *EventBus) RequestMany(
+	ctx context.Context,
+	topic string,
+	data interface{},
+	maxReplies int,
+) ([]interface{}, error) {
+	inbox, queue := eb.openInbox()
+	defer eb.closeInbox(inbox)
+
+	count, err := eb.publishAsync(Event{ //nolint:exhaustivestruct
+		Data:    data,
+		Topic:   topic,
+		ctx:     ctx,
+		bus:     eb,
+		replyTo: inbox,
+	}, newPublishOptions(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, ErrNoResponders
+	}
+
+	var replies []interface{}
+
+	for maxReplies <= 0 || len(replies) < maxReplies {
+		if item, ok := queue.pop(); ok {
+			replies = append(replies, item.evt.Data)
+
+			continue
+		}
+
+		select {
+		case <-queue.notify:
+		case <-eb.done:
+			return replies, ErrClosed
+		case <-ctx.Done():
+			if len(replies) == 0 {
+				return nil, ctx.Err()
+			}
+
+			return replies, nil
+		}
+	}
+
+	return replies, nil
+}
+
+// openInbox registers a unique inbox receiving the replies to a request.
+func (eb *EventBus) openInbox() (string, *eventQueue) {
+	eb.inboxMu.Lock()
+	defer eb.inboxMu.Unlock()
+
+	inbox := inboxPrefix + strconv.FormatUint(atomic.AddUint64(&eb.lastInboxID, 1), 10)
+	queue := newEventQueue()
+	eb.inboxes[inbox] = queue
+
+	return inbox, queue
+}
+
+// closeInbox removes an inbox, later replies to the request are discarded.
+func (eb *EventBus) closeInbox(inbox string) {
+	eb.inboxMu.Lock()
+	defer eb.inboxMu.Unlock()
+
+	delete(eb.inboxes, inbox)
+}
+
+// reply hands a reply to the inbox of the request. Replies to finished requests are discarded.
+func (eb *EventBus) reply(inbox string, data interface{}) error {
+	eb.mu.RLock()
+	closed := eb.closed
+	eb.mu.RUnlock()
+
+	if closed {
+		return ErrClosed
+	}
+
+	eb.inboxMu.Lock()
+	queue, ok := eb.inboxes[inbox]
+	eb.inboxMu.Unlock()
+
+	if ok {
+		queue.push(queuedEvent{
+			evt:     Event{Data: data}, //nolint:exhaustivestruct
+			release: func() {},
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package eventbus_test
+
+import (
+	"context"
+	eb "github.com/dtomasi/go-event-bus/v3"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestEventBus_Request(t *testing.T) {
+	ebi := eb.NewEventBus()
+	sub, err := ebi.Subscribe("math:double")
+	assert.NoError(t, err)
+
+	go func() {
+		for evt := range sub.Channel() {
+			assert.NotEmpty(t, evt.ReplyTo())
+			assert.NoError(t, evt.Respond(evt.Data.(int)*2))
+		}
+	}()
+
+	reply, err := ebi.Request(context.Background(), "math:double", 21)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, reply)
+
+	_, err = ebi.Request(context.Background(), "math:triple", 21)
+	assert.ErrorIs(t, err, eb.ErrNoResponders)
+}
+
+func TestEventBus_RequestTimeout(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	// Subscriber never responds
+	_, err := ebi.SubscribeCallback("foo", func(topic string, data interface{}) {})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = ebi.Request(ctx, "foo", "bar")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestEventBus_RequestMany(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	for i := 0; i < 3; i++ {
+		sub, err := ebi.Subscribe("scatter:*")
+		assert.NoError(t, err)
+
+		go func(n int) {
+			for evt := range sub.Channel() {
+				assert.NoError(t, evt.Respond(n))
+			}
+		}(i)
+	}
+
+	// Gather until max count
+	replies, err := ebi.RequestMany(context.Background(), "scatter:gather", nil, 3)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []interface{}{0, 1, 2}, replies)
+
+	// Gather until timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	replies, err = ebi.RequestMany(ctx, "scatter:gather", nil, 0)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []interface{}{0, 1, 2}, replies)
+}
+
+func TestEvent_RespondWithoutRequest(t *testing.T) {
+	ebi := eb.NewEventBus()
+	sub, err := ebi.Subscribe("foo")
+	assert.NoError(t, err)
+
+	go func() {
+		evt := <-sub.Channel()
+		assert.ErrorIs(t, evt.Respond("bar"), eb.ErrNoReplyTopic)
+		evt.Done()
+	}()
+
+	_, err = ebi.Publish("foo", "bar")
+	assert.NoError(t, err)
+}
+
+func TestEventBus_RequestSharedInbox(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	_, err := ebi.SubscribeEvent("math:double", func(evt eb.Event) error {
+		return evt.Respond(evt.Data.(int) * 2)
+	})
+	assert.NoError(t, err)
+
+	// Replies are not published to any topic
+	all, err := ebi.Subscribe("#", eb.WithBufferSize(100))
+	assert.NoError(t, err)
+
+	_, err = ebi.Request(context.Background(), "math:double", 1)
+	assert.NoError(t, err)
+
+	topics := len(ebi.Stats().GetTopicStats())
+
+	// Requests neither create topics nor receive the replies of other requests
+	for i := 0; i < 10; i++ {
+		reply, err := ebi.Request(context.Background(), "math:double", i)
+		assert.NoError(t, err)
+		assert.Equal(t, i*2, reply)
+	}
+
+	assert.Len(t, ebi.Stats().GetTopicStats(), topics)
+
+	for i := 0; i < 11; i++ {
+		assert.Equal(t, "math:double", (<-all.Channel()).Topic)
+	}
+
+	assert.Len(t, all.Channel(), 0)
+}
+
+func TestEventBus_RequestClosed(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	// Subscriber never responds
+	_, err := ebi.SubscribeCallback("foo", func(topic string, data interface{}) {})
+	assert.NoError(t, err)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		assert.NoError(t, ebi.Close())
+	}()
+
+	_, err = ebi.Request(context.Background(), "foo", "bar")
+	assert.ErrorIs(t, err, eb.ErrClosed)
+}
@@ -0,0 +1,67 @@
+package eventbus
+
+import (
+	"sort"
+)
+
+// retain stores the event as the retained event of its topic.
+func (eb *EventBus) retain(evt Event) {
+	eb.retainedMu.Lock()
+	defer eb.retainedMu.Unlock()
+
+	eb.retained[evt.Topic] = evt.detached()
+}
+
+// matchRetained returns the retained events of all topics matching the pattern, ordered by topic.
+func (eb *EventBus) matchRetained(pattern string) []Event {
+	eb.retainedMu.Lock()
+	defer eb.retainedMu.Unlock()
+
+	var events []Event
+
+	for topic, evt := range eb.retained {
+		if pattern == topic || eb.matcher.Match(pattern, topic) {
+			events = append(events, evt)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Topic < events[j].Topic
+	})
+
+	return events
+}
+
+// Retained returns the retained event of a topic.
+func (eb *EventBus) Retained(topic string) (Event, bool) {
+	eb.retainedMu.Lock()
+	defer eb.retainedMu.Unlock()
+
+	evt, ok := eb.retained[topic]
+
+	return evt, ok
+}
+
+// RetainedTopics returns the sorted names of all topics holding a retained event.
+func (eb *EventBus) RetainedTopics() []string {
+	eb.retainedMu.Lock()
+	defer eb.retainedMu.Unlock()
+
+	topics := make([]string, 0, len(eb.retained))
+	for topic := range eb.retained {
+		topics = append(topics, topic)
+	}
+
+	sort.Strings(topics)
+
+	return topics
+}
+
+// ClearRetained removes the retained event of a topic.
+// Subscriptions that already received the event are not affected.
+func (eb *EventBus) ClearRetained(topic string) {
+	eb.retainedMu.Lock()
+	defer eb.retainedMu.Unlock()
+
+	delete(eb.retained, topic)
+}
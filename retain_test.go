@@ -0,0 +1,100 @@
+package eventbus_test
+
+import (
+	"context"
+	eb "github.com/dtomasi/go-event-bus/v3"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEventBus_Retained(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	_, err := ebi.Publish("app:ready", "v1", eb.WithRetain())
+	assert.NoError(t, err)
+
+	_, err = ebi.Publish("app:ready", "v2", eb.WithRetain())
+	assert.NoError(t, err)
+
+	// Events published without WithRetain are not retained
+	_, err = ebi.Publish("app:stopped", "v1")
+	assert.NoError(t, err)
+
+	evt, ok := ebi.Retained("app:ready")
+	assert.True(t, ok)
+	assert.Equal(t, "v2", evt.Data)
+
+	_, ok = ebi.Retained("app:stopped")
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{"app:ready"}, ebi.RetainedTopics())
+
+	ebi.ClearRetained("app:ready")
+
+	_, ok = ebi.Retained("app:ready")
+	assert.False(t, ok)
+	assert.Empty(t, ebi.RetainedTopics())
+}
+
+func TestEventBus_RetainedLateSubscriber(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	assert.NoError(t, ebi.PublishAsyncOnce("app:ready", true, eb.WithRetain()))
+	assert.NoError(t, ebi.PublishAsync("app:config", "cfg", eb.WithRetain()))
+
+	sub, err := ebi.Subscribe("app:ready")
+	assert.NoError(t, err)
+
+	evt := <-sub.Channel()
+	assert.Equal(t, "app:ready", evt.Topic)
+	assert.Equal(t, true, evt.Data)
+
+	// Wildcard subscribers receive the retained events of all matching topics, followed by live events
+	wildcard, err := ebi.Subscribe("app:#")
+	assert.NoError(t, err)
+
+	assert.NoError(t, ebi.PublishAsync("app:config", "live"))
+
+	var received []interface{}
+	for i := 0; i < 3; i++ {
+		received = append(received, (<-wildcard.Channel()).Data)
+	}
+
+	assert.Equal(t, []interface{}{"cfg", true, "live"}, received)
+}
+
+func TestEventBus_RetainedCallback(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	_, err := ebi.Publish("app:ready", "v1", eb.WithRetain())
+	assert.NoError(t, err)
+
+	received := make(chan interface{}, 1)
+
+	_, err = ebi.SubscribeCallback("app:*", func(topic string, data interface{}) {
+		received <- data
+	}, eb.WithOverflowPolicy(eb.OverflowFail), eb.WithBufferSize(1))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "v1", <-received)
+}
+
+func TestEventBus_RetainedGroup(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	_, err := ebi.Publish("jobs", "job", eb.WithRetain())
+	assert.NoError(t, err)
+
+	first, err := ebi.SubscribeGroup("jobs", "workers", eb.WithBufferSize(1))
+	assert.NoError(t, err)
+
+	second, err := ebi.SubscribeGroup("jobs", "workers", eb.WithBufferSize(1))
+	assert.NoError(t, err)
+
+	_, err = ebi.Drain(context.Background())
+	assert.NoError(t, err)
+
+	// Only the member creating the group receives the retained event
+	assert.Len(t, first.Channel(), 1)
+	assert.Len(t, second.Channel(), 0)
+}
@@ -0,0 +1,95 @@
+package eventbus
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// RetryFailureHandler is called once an event finally failed after attempts calls of the handler.
+type RetryFailureHandler func(sub *Subscription, evt Event, err error, attempts int)
+
+// RetryPolicy defines how often and when a failed handler is called again, see WithRetryPolicy.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of handler calls per event, including the first one.
+	MaxAttempts int
+	// InitialBackoff is the time to wait before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff limits the time to wait between retries. Zero means no limit.
+	MaxBackoff time.Duration
+	// Multiplier grows the backoff after every retry. Values below 1 default to 2.
+	Multiplier float64
+	// Jitter randomly shortens every backoff by up to the given fraction, between 0 and 1.
+	Jitter float64
+	// Retryable reports whether an error is retried. By default all errors, including panics, are retried.
+	Retryable func(err error) bool
+	// OnFailure is called when an event finally failed.
+	OnFailure RetryFailureHandler
+}
+
+// retryable reports whether the error is retried.
+func (p *RetryPolicy) retryable(err error) bool {
+	return p.Retryable == nil || p.Retryable(err)
+}
+
+// backoff returns the time to wait before the given retry, starting with 1.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(retry-1))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		backoff -= backoff * math.Min(p.Jitter, 1) * rand.Float64() //nolint:gosec
+	}
+
+	return time.Duration(backoff)
+}
+
+// retryHandler calls the handler again according to the retry policy of the subscription
+// until it succeeds. It returns the number of handler calls and the error of the last call if all attempts failed,
+// the error is not retryable or the subscription was stopped while waiting.
+func (eb *EventBus) retryHandler(sub *Subscription, handler EventHandler, evt Event, err error) (int, error) {
+	policy := sub.retryPolicy
+	attempts := 1
+
+	for attempts < policy.MaxAttempts && policy.retryable(err) {
+		if !waitBackoff(sub, evt, policy.backoff(attempts)) {
+			break
+		}
+
+		eb.stats.incRetryCountByTopic(evt.Topic)
+		attempts++
+
+		if err = eb.callHandler(sub, handler, evt); err == nil {
+			return attempts, nil
+		}
+	}
+
+	if policy.OnFailure != nil {
+		policy.OnFailure(sub, evt, err, attempts)
+	}
+
+	return attempts, err
+}
+
+// waitBackoff blocks for the given duration. It returns false if the subscription was stopped
+// or the event context expired in the meantime.
+func waitBackoff(sub *Subscription, evt Event, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-sub.done:
+		return false
+	case <-evt.Context().Done():
+		return false
+	}
+}
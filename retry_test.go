@@ -0,0 +1,114 @@
+package eventbus_test
+
+import (
+	"errors"
+	eb "github.com/dtomasi/go-event-bus/v3"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestEventBus_RetryPolicy(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	calls := eb.NewSafeCounter()
+
+	_, err := ebi.SubscribeHandler("orders", func(topic string, data interface{}) error {
+		calls.Inc()
+
+		if calls.Value() < 3 {
+			return errTest
+		}
+
+		return nil
+	}, eb.WithRetryPolicy(eb.RetryPolicy{ //nolint:exhaustivestruct
+		MaxAttempts:    5,
+		InitialBackoff: 10 * time.Millisecond,
+		Jitter:         0.5,
+	}))
+	assert.NoError(t, err)
+
+	start := time.Now()
+
+	assert.NoError(t, ebi.PublishE("orders", "order"))
+
+	// Backoff grows exponentially: at least 5ms and 10ms with jitter
+	assert.GreaterOrEqual(t, time.Since(start), 15*time.Millisecond)
+	assert.Equal(t, 3, calls.Value())
+	assert.Equal(t, 2, ebi.Stats().GetRetryCountByTopic("orders"))
+}
+
+func TestEventBus_RetryPolicyFinalFailure(t *testing.T) {
+	ebi := eb.NewEventBus(
+		eb.WithDeadLetterTopic("dead"),
+		eb.WithPanicHandler(func(sub *eb.Subscription, evt eb.Event, err *eb.PanicError) {}),
+	)
+
+	dead, err := ebi.Subscribe("dead", eb.WithBufferSize(1))
+	assert.NoError(t, err)
+
+	var (
+		failedErr      error
+		failedAttempts int
+	)
+
+	policy := eb.RetryPolicy{ //nolint:exhaustivestruct
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+		OnFailure: func(sub *eb.Subscription, evt eb.Event, err error, attempts int) {
+			assert.Equal(t, "order", evt.Data)
+
+			failedErr = err
+			failedAttempts = attempts
+		},
+	}
+
+	// Panics are retried as well
+	_, err = ebi.SubscribeCallback("orders", func(topic string, data interface{}) {
+		panic("boom")
+	}, eb.WithRetryPolicy(policy))
+	assert.NoError(t, err)
+
+	var panicErr *eb.PanicError
+
+	assert.ErrorAs(t, ebi.PublishE("orders", "order"), &panicErr)
+	assert.ErrorAs(t, failedErr, &panicErr)
+	assert.Equal(t, 3, failedAttempts)
+	assert.Equal(t, 2, ebi.Stats().GetRetryCountByTopic("orders"))
+
+	// The dead letter reports all handler calls
+	letter, ok := (<-dead.Channel()).Data.(eb.DeadLetter)
+	assert.True(t, ok)
+	assert.Equal(t, 3, letter.Attempts)
+}
+
+func TestEventBus_RetryPolicyRetryable(t *testing.T) {
+	errPermanent := errors.New("permanent")
+
+	ebi := eb.NewEventBus()
+
+	calls := eb.NewSafeCounter()
+	failures := eb.NewSafeCounter()
+
+	_, err := ebi.SubscribeHandler("orders", func(topic string, data interface{}) error {
+		calls.Inc()
+
+		return errPermanent
+	}, eb.WithRetryPolicy(eb.RetryPolicy{ //nolint:exhaustivestruct
+		MaxAttempts: 5,
+		Retryable: func(err error) bool {
+			return !errors.Is(err, errPermanent)
+		},
+		OnFailure: func(sub *eb.Subscription, evt eb.Event, err error, attempts int) {
+			assert.Equal(t, 1, attempts)
+			failures.Inc()
+		},
+	}))
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, ebi.PublishE("orders", "order"), errPermanent)
+	assert.Equal(t, 1, calls.Value())
+	assert.Equal(t, 1, failures.Value())
+	assert.Equal(t, 0, ebi.Stats().GetRetryCountByTopic("orders"))
+}
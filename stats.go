@@ -1,30 +1,71 @@
 package eventbus
 
+import (
+	"sync"
+)
+
 type TopicStats struct {
-	Name            string
-	PublishedCount  *SafeCounter
-	SubscriberCount *SafeCounter
+	Name              string
+	PublishedCount    *SafeCounter
+	SubscriberCount   *SafeCounter
+	DroppedCount      *SafeCounter
+	DeadLetteredCount *SafeCounter
+	RetryCount        *SafeCounter
+	FilteredCount     *SafeCounter
 }
 
 type topicStatsMap map[string]*TopicStats
 
+// PartitionStats holds the queue depth of every partition of a subscription using WithPartitions.
+type PartitionStats struct {
+	SubscriptionID uint64
+	Topic          string
+	QueueDepth     []*SafeCounter
+}
+
+// GroupStats holds the member count and the number of events delivered to a consumer group.
+type GroupStats struct {
+	Name           string
+	MemberCount    *SafeCounter
+	DeliveredCount *SafeCounter
+}
+
 type Stats struct {
-	data topicStatsMap
+	mu         sync.RWMutex
+	data       topicStatsMap
+	partitions map[uint64]*PartitionStats
+	groups     map[string]*GroupStats
 }
 
 func newStats() *Stats {
-	return &Stats{
-		data: map[string]*TopicStats{},
+	return &Stats{ //nolint:exhaustivestruct
+		data:       map[string]*TopicStats{},
+		partitions: map[uint64]*PartitionStats{},
+		groups:     map[string]*GroupStats{},
 	}
 }
 
 func (s *Stats) getOrCreateTopicStats(topicName string) *TopicStats {
-	_, ok := s.data[topicName]
-	if !ok {
+	s.mu.RLock()
+	tStats, ok := s.data[topicName]
+	s.mu.RUnlock()
+
+	if ok {
+		return tStats
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok = s.data[topicName]; !ok {
 		s.data[topicName] = &TopicStats{
-			Name:            topicName,
-			PublishedCount:  NewSafeCounter(),
-			SubscriberCount: NewSafeCounter(),
+			Name:              topicName,
+			PublishedCount:    NewSafeCounter(),
+			SubscriberCount:   NewSafeCounter(),
+			DroppedCount:      NewSafeCounter(),
+			DeadLetteredCount: NewSafeCounter(),
+			RetryCount:        NewSafeCounter(),
+			FilteredCount:     NewSafeCounter(),
 		}
 	}
 
@@ -35,6 +76,10 @@ func (s *Stats) incSubscriberCountByTopic(topicName string) {
 	s.getOrCreateTopicStats(topicName).SubscriberCount.Inc()
 }
 
+func (s *Stats) decSubscriberCountByTopic(topicName string) {
+	s.getOrCreateTopicStats(topicName).SubscriberCount.Dec()
+}
+
 func (s *Stats) GetSubscriberCountByTopic(topicName string) int {
 	return s.getOrCreateTopicStats(topicName).SubscriberCount.Value()
 }
@@ -47,7 +92,42 @@ func (s *Stats) GetPublishedCountByTopic(topicName string) int {
 	return s.getOrCreateTopicStats(topicName).PublishedCount.Value()
 }
 
+func (s *Stats) incDroppedCountByTopic(topicName string) {
+	s.getOrCreateTopicStats(topicName).DroppedCount.Inc()
+}
+
+func (s *Stats) GetDroppedCountByTopic(topicName string) int {
+	return s.getOrCreateTopicStats(topicName).DroppedCount.Value()
+}
+
+func (s *Stats) incDeadLetteredCountByTopic(topicName string) {
+	s.getOrCreateTopicStats(topicName).DeadLetteredCount.Inc()
+}
+
+func (s *Stats) GetDeadLetteredCountByTopic(topicName string) int {
+	return s.getOrCreateTopicStats(topicName).DeadLetteredCount.Value()
+}
+
+func (s *Stats) incRetryCountByTopic(topicName string) {
+	s.getOrCreateTopicStats(topicName).RetryCount.Inc()
+}
+
+func (s *Stats) GetRetryCountByTopic(topicName string) int {
+	return s.getOrCreateTopicStats(topicName).RetryCount.Value()
+}
+
+func (s *Stats) incFilteredCountByTopic(topicName string) {
+	s.getOrCreateTopicStats(topicName).FilteredCount.Inc()
+}
+
+func (s *Stats) GetFilteredCountByTopic(topicName string) int {
+	return s.getOrCreateTopicStats(topicName).FilteredCount.Value()
+}
+
 func (s *Stats) GetTopicStats() []*TopicStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	var tStatsSlice []*TopicStats
 	for _, tStats := range s.data {
 		tStatsSlice = append(tStatsSlice, tStats)
@@ -59,3 +139,99 @@ func (s *Stats) GetTopicStats() []*TopicStats {
 func (s *Stats) GetTopicStatsByName(topicName string) *TopicStats {
 	return s.getOrCreateTopicStats(topicName)
 }
+
+func (s *Stats) addPartitionStats(sub *Subscription, partitions int) *PartitionStats {
+	pStats := &PartitionStats{
+		SubscriptionID: sub.ID(),
+		Topic:          sub.Topic(),
+		QueueDepth:     make([]*SafeCounter, partitions),
+	}
+
+	for i := range pStats.QueueDepth {
+		pStats.QueueDepth[i] = NewSafeCounter()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.partitions[pStats.SubscriptionID] = pStats
+
+	return pStats
+}
+
+func (s *Stats) removePartitionStats(subscriptionID uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.partitions, subscriptionID)
+}
+
+func (s *Stats) GetPartitionStats() []*PartitionStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var pStatsSlice []*PartitionStats
+	for _, pStats := range s.partitions {
+		pStatsSlice = append(pStatsSlice, pStats)
+	}
+
+	return pStatsSlice
+}
+
+func (s *Stats) GetPartitionStatsBySubscription(subscriptionID uint64) *PartitionStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.partitions[subscriptionID]
+}
+
+func (s *Stats) getOrCreateGroupStats(groupName string) *GroupStats {
+	s.mu.RLock()
+	gStats, ok := s.groups[groupName]
+	s.mu.RUnlock()
+
+	if ok {
+		return gStats
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok = s.groups[groupName]; !ok {
+		s.groups[groupName] = &GroupStats{
+			Name:           groupName,
+			MemberCount:    NewSafeCounter(),
+			DeliveredCount: NewSafeCounter(),
+		}
+	}
+
+	return s.groups[groupName]
+}
+
+func (s *Stats) incGroupMemberCount(groupName string) {
+	s.getOrCreateGroupStats(groupName).MemberCount.Inc()
+}
+
+func (s *Stats) decGroupMemberCount(groupName string) {
+	s.getOrCreateGroupStats(groupName).MemberCount.Dec()
+}
+
+func (s *Stats) incGroupDeliveredCount(groupName string) {
+	s.getOrCreateGroupStats(groupName).DeliveredCount.Inc()
+}
+
+func (s *Stats) GetGroupStats() []*GroupStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var gStatsSlice []*GroupStats
+	for _, gStats := range s.groups {
+		gStatsSlice = append(gStatsSlice, gStats)
+	}
+
+	return gStatsSlice
+}
+
+func (s *Stats) GetGroupStatsByName(groupName string) *GroupStats {
+	return s.getOrCreateGroupStats(groupName)
+}
@@ -0,0 +1,306 @@
+package eventbus
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Subscription is a handle to a subscriber registered on an EventBus.
+// It can be used to unsubscribe from the topic it was created for.
+type Subscription struct {
+	id    uint64
+	topic string
+	ch    EventChannel
+	bus   *EventBus
+
+	// owned is true if the channel was created by the EventBus and may therefore be closed by it.
+	owned bool
+
+	overflowPolicy OverflowPolicy
+	overflowTopic  string
+	deliveryMode   DeliveryMode
+	queue          *eventQueue
+	handled        bool
+	group          string
+	ackTimeout     time.Duration
+	maxAttempts    int
+	retryPolicy    *RetryPolicy
+	filters        []Filter
+	priority       int
+
+	// active is the number of events currently handled by handler workers.
+	active int32
+
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+	once   sync.Once
+}
+
+func newSubscription(
+	bus *EventBus,
+	id uint64,
+	topic string,
+	ch EventChannel,
+	owned bool,
+	o *subscribeOptions,
+) *Subscription {
+	sub := &Subscription{ //nolint:exhaustivestruct
+		id:             id,
+		topic:          topic,
+		ch:             ch,
+		bus:            bus,
+		owned:          owned,
+		overflowPolicy: o.overflowPolicy,
+		overflowTopic:  o.overflowTopic,
+		deliveryMode:   o.deliveryMode,
+		handled:        o.handled,
+		group:          o.group,
+		ackTimeout:     o.ackTimeout,
+		maxAttempts:    o.maxAttempts,
+		retryPolicy:    o.retryPolicy,
+		filters:        o.filters,
+		priority:       o.priority,
+		done:           make(chan struct{}),
+	}
+
+	if sub.ordered() {
+		sub.queue = newEventQueue()
+	}
+
+	return sub
+}
+
+// ordered reports whether events are delivered through the queue of the subscription.
+func (s *Subscription) ordered() bool {
+	return s.overflowPolicy == OverflowBlock && s.deliveryMode == DeliveryOrdered
+}
+
+// ID returns the unique id of the subscription within its EventBus.
+func (s *Subscription) ID() uint64 {
+	return s.id
+}
+
+// Topic returns the topic (or wildcard pattern) the subscription was created for.
+func (s *Subscription) Topic() string {
+	return s.topic
+}
+
+// Channel returns the EventChannel events are delivered to.
+func (s *Subscription) Channel() EventChannel {
+	return s.ch
+}
+
+// Group returns the consumer group of the subscription or an empty string.
+func (s *Subscription) Group() string {
+	return s.group
+}
+
+// Priority returns the priority of the subscription, see WithPriority.
+func (s *Subscription) Priority() int {
+	return s.priority
+}
+
+// load returns the number of events queued, buffered or handled by the subscription.
+func (s *Subscription) load() int {
+	load := len(s.ch) + int(atomic.LoadInt32(&s.active))
+
+	if s.queue != nil {
+		load += s.queue.len()
+	}
+
+	return load
+}
+
+// Unsubscribe removes the subscription from the EventBus.
+// Channels created by the EventBus are closed, channels passed to SubscribeChannel are left open.
+// Calling Unsubscribe more than once is a no-op.
+func (s *Subscription) Unsubscribe() {
+	s.bus.unsubscribe(s)
+}
+
+// deliver sends the event to the subscription channel.
+// If the subscription is stopped while waiting for a receiver the event is marked as done.
+// If the event context expires while waiting the event is dropped without acknowledgement.
+func (s *Subscription) deliver(evt Event) {
+	evt.sub = s
+	evt = s.track(evt)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		evt.Done()
+
+		return
+	}
+
+	select {
+	case s.ch <- evt:
+		s.handedOver(evt)
+	case <-s.done:
+		evt.Done()
+	case <-evt.Context().Done():
+	}
+}
+
+// enqueue appends the event to the queue of the subscription.
+// release is called once the event was handed over to the subscription.
+func (s *Subscription) enqueue(evt Event, release func()) {
+	evt.sub = s
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		evt.Done()
+		release()
+
+		return
+	}
+
+	s.queue.push(queuedEvent{
+		evt:     evt,
+		release: release,
+	})
+}
+
+// dispatch delivers queued events in order until the subscription is stopped.
+func (s *Subscription) dispatch() {
+	for {
+		item, ok := s.queue.pop()
+		if ok {
+			s.deliver(item.evt)
+			item.release()
+
+			continue
+		}
+
+		select {
+		case <-s.queue.notify:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// tryDeliver sends the event to the subscription channel without blocking.
+// Using OverflowDropOldest the oldest buffered event is dropped to make room for the event.
+// It returns ErrOverflow if the event could not be delivered.
+func (s *Subscription) tryDeliver(evt Event) error {
+	evt.sub = s
+	evt = s.track(evt)
+
+	oldest, err := s.offer(evt)
+	if oldest != nil {
+		s.bus.drop(s, *oldest)
+	}
+
+	return err
+}
+
+// offerAll hands over the events without blocking and returns the events that overflowed,
+// including the oldest events dropped using OverflowDropOldest. Overflowing events are not handled,
+// so offerAll can be called while holding the lock of the EventBus.
+func (s *Subscription) offerAll(events []Event) []Event {
+	var overflowed []Event
+
+	for _, evt := range events {
+		delivered := evt
+		delivered.sub = s
+
+		oldest, err := s.offer(s.track(delivered))
+		if oldest != nil {
+			overflowed = append(overflowed, *oldest)
+		}
+
+		if err != nil {
+			overflowed = append(overflowed, evt)
+		}
+	}
+
+	return overflowed
+}
+
+// offer implements tryDeliver and returns the oldest event if it was dropped.
+func (s *Subscription) offer(evt Event) (*Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		evt.Done()
+
+		return nil, nil
+	}
+
+	select {
+	case s.ch <- evt:
+		s.handedOver(evt)
+
+		return nil, nil
+	default:
+	}
+
+	if s.overflowPolicy != OverflowDropOldest {
+		return nil, ErrOverflow
+	}
+
+	var oldest *Event
+
+	select {
+	case evt := <-s.ch:
+		oldest = &evt
+	default:
+	}
+
+	select {
+	case s.ch <- evt:
+		s.handedOver(evt)
+
+		return oldest, nil
+	default:
+		return oldest, ErrOverflow
+	}
+}
+
+// handedOver starts the acknowledgement timeout of an event received by the subscriber.
+func (s *Subscription) handedOver(evt Event) {
+	if evt.delivery != nil {
+		evt.delivery.arm(evt.attempt)
+	}
+}
+
+// stop releases all pending deliveries and closes the channel if it is owned by the EventBus.
+func (s *Subscription) stop() {
+	s.once.Do(func() {
+		close(s.done)
+
+		// Wait for pending deliveries to be released before closing the channel.
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.closed = true
+
+		// Release events that were not dispatched anymore.
+		if s.queue != nil {
+			for item, ok := s.queue.pop(); ok; item, ok = s.queue.pop() {
+				item.evt.Done()
+				item.release()
+			}
+		}
+
+		if s.owned {
+			close(s.ch)
+		}
+
+		// Release buffered events that will not be handled anymore.
+		if s.handled {
+			for evt := range s.ch {
+				evt.Done()
+			}
+		}
+
+		s.bus.stats.removePartitionStats(s.id)
+	})
+}
@@ -0,0 +1,94 @@
+package eventbus_test
+
+import (
+	eb "github.com/dtomasi/go-event-bus/v3"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSubscription_Unsubscribe(t *testing.T) {
+	const testTopicName = "foo:bar"
+
+	ebi := eb.NewEventBus()
+	sub, err := ebi.Subscribe(testTopicName)
+	assert.NoError(t, err)
+
+	assert.True(t, ebi.HasSubscribers(testTopicName))
+	assert.Equal(t, 1, ebi.Stats().GetSubscriberCountByTopic(testTopicName))
+
+	sub.Unsubscribe()
+
+	assert.False(t, ebi.HasSubscribers(testTopicName))
+	assert.Equal(t, 0, ebi.Stats().GetSubscriberCountByTopic(testTopicName))
+
+	// Channels created by the bus are closed on unsubscribe
+	_, ok := <-sub.Channel()
+	assert.False(t, ok)
+
+	// Unsubscribing twice must not panic
+	sub.Unsubscribe()
+
+	// Publishing must not block without receivers
+	ebi.Publish(testTopicName, "bar")
+}
+
+func TestSubscription_UnsubscribeReleasesPublish(t *testing.T) {
+	const testTopicName = "foo:bar"
+
+	ebi := eb.NewEventBus()
+	sub, err := ebi.Subscribe(testTopicName)
+	assert.NoError(t, err)
+
+	done := make(chan struct{})
+
+	go func() {
+		ebi.Publish(testTopicName, "bar")
+		close(done)
+	}()
+
+	// Nobody reads the channel, unsubscribing releases the pending publish
+	sub.Unsubscribe()
+
+	<-done
+}
+
+func TestEventBus_UnsubscribeChannel(t *testing.T) {
+	ebi := eb.NewEventBus()
+	ch := eb.NewEventChannel()
+	_, err := ebi.SubscribeChannel("foo", ch)
+	assert.NoError(t, err)
+	_, err = ebi.SubscribeChannel("bar", ch)
+	assert.NoError(t, err)
+	other, err := ebi.Subscribe("foo")
+	assert.NoError(t, err)
+
+	ebi.UnsubscribeChannel("foo", ch)
+
+	assert.True(t, ebi.HasSubscribers("foo"))
+	assert.True(t, ebi.HasSubscribers("bar"))
+	assert.Equal(t, 1, ebi.Stats().GetSubscriberCountByTopic("foo"))
+
+	other.Unsubscribe()
+
+	assert.False(t, ebi.HasSubscribers("foo"))
+}
+
+func TestEventBus_UnsubscribeAll(t *testing.T) {
+	ebi := eb.NewEventBus()
+	_, err := ebi.Subscribe("foo")
+	assert.NoError(t, err)
+	_, err = ebi.SubscribeChannel("foo", eb.NewEventChannel())
+	assert.NoError(t, err)
+	_, err = ebi.SubscribeCallback("foo", func(topic string, data interface{}) {})
+	assert.NoError(t, err)
+	_, err = ebi.Subscribe("bar")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, ebi.Stats().GetSubscriberCountByTopic("foo"))
+
+	ebi.UnsubscribeAll("foo")
+
+	assert.False(t, ebi.HasSubscribers("foo"))
+	assert.True(t, ebi.HasSubscribers("bar"))
+	assert.Equal(t, 0, ebi.Stats().GetSubscriberCountByTopic("foo"))
+}
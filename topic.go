@@ -0,0 +1,84 @@
+package eventbus
+
+import (
+	"context"
+)
+
+// Topic is a typed view on a single topic of an EventBus.
+// Events published through a Topic can still be received by untyped (wildcard) subscribers.
+// Typed subscribers ignore events on the topic whose data is not of type T.
+type Topic[T any] struct {
+	bus  *EventBus
+	name string
+}
+
+// NewTopic returns a new Topic bound to the given EventBus.
+func NewTopic[T any](bus *EventBus, name string) *Topic[T] {
+	return &Topic[T]{
+		bus:  bus,
+		name: name,
+	}
+}
+
+// Name returns the name of the topic.
+func (t *Topic[T]) Name() string {
+	return t.name
+}
+
+// Publish data to the topic and wait for all subscribers to finish.
+func (t *Topic[T]) Publish(data T, opts ...PublishOption) error {
+	return t.bus.PublishCtx(context.Background(), t.name, data, opts...)
+}
+
+// PublishCtx same as Publish but stops waiting for subscribers once the context expires.
+func (t *Topic[T]) PublishCtx(ctx context.Context, data T, opts ...PublishOption) error {
+	return t.bus.PublishCtx(ctx, t.name, data, opts...)
+}
+
+// PublishAsync data to the topic asynchronously.
+func (t *Topic[T]) PublishAsync(data T, opts ...PublishOption) error {
+	return t.bus.PublishAsyncCtx(context.Background(), t.name, data, opts...)
+}
+
+// PublishAsyncCtx same as PublishAsync but deliveries are abandoned once the context expires.
+func (t *Topic[T]) PublishAsyncCtx(ctx context.Context, data T, opts ...PublishOption) error {
+	return t.bus.PublishAsyncCtx(ctx, t.name, data, opts...)
+}
+
+// Subscribe returns a typed channel receiving the data of all events on the topic.
+// Events are acknowledged as soon as the data was received from the channel.
+// The channel is closed when the Subscription is removed.
+func (t *Topic[T]) Subscribe() (<-chan T, *Subscription, error) {
+	sub, err := t.bus.Subscribe(t.name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := make(chan T)
+
+	go func() {
+		defer close(out)
+
+		for evt := range sub.ch {
+			if data, ok := evt.Data.(T); ok {
+				select {
+				case out <- data:
+				case <-sub.done:
+				}
+			}
+
+			evt.Done()
+		}
+	}()
+
+	return out, sub, nil
+}
+
+// SubscribeFunc registers a typed callback for the topic.
+func (t *Topic[T]) SubscribeFunc(fn func(data T), opts ...SubscribeOption) (*Subscription, error) {
+	return t.bus.SubscribeCallback(t.name, func(topic string, data interface{}) {
+		if typed, ok := data.(T); ok {
+			fn(typed)
+		}
+	}, opts...)
+}
@@ -0,0 +1,65 @@
+package eventbus_test
+
+import (
+	eb "github.com/dtomasi/go-event-bus/v3"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testPayload struct {
+	Name string
+}
+
+func TestTopic_SubscribeFunc(t *testing.T) {
+	ebi := eb.NewEventBus()
+	topic := eb.NewTopic[testPayload](ebi, "foo:bar")
+
+	var typed []string
+
+	_, err := topic.SubscribeFunc(func(data testPayload) {
+		typed = append(typed, data.Name)
+	})
+	assert.NoError(t, err)
+
+	// Untyped wildcard subscribers receive typed events as well
+	untyped := eb.NewSafeCounter()
+
+	_, err = ebi.SubscribeCallback("foo:*", func(topic string, data interface{}) {
+		if _, ok := data.(testPayload); ok {
+			untyped.Inc()
+		}
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, topic.Publish(testPayload{Name: "baz"}))
+
+	// Events with mismatching data are ignored by typed subscribers
+	_, err = ebi.Publish("foo:bar", "not a payload")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"baz"}, typed)
+	assert.Equal(t, 1, untyped.Value())
+}
+
+func TestTopic_Subscribe(t *testing.T) {
+	ebi := eb.NewEventBus()
+	topic := eb.NewTopic[int](ebi, "numbers")
+
+	ch, sub, err := topic.Subscribe()
+	assert.NoError(t, err)
+
+	go func() {
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, topic.Publish(i))
+		}
+
+		sub.Unsubscribe()
+	}()
+
+	var received []int
+	for v := range ch {
+		received = append(received, v)
+	}
+
+	assert.Equal(t, []int{0, 1, 2}, received)
+}
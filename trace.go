@@ -0,0 +1,191 @@
+package eventbus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TraceParentHeader is the event header holding the W3C traceparent of an event.
+const TraceParentHeader = "traceparent"
+
+// TraceFlagsSampled is the W3C trace flag marking a trace as sampled.
+const TraceFlagsSampled byte = 0x01
+
+// SpanContext identifies a span within a trace, compatible with the W3C trace context.
+type SpanContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	Flags   byte
+}
+
+// IsValid reports whether both trace and span ID are set.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != [16]byte{} && sc.SpanID != [8]byte{}
+}
+
+// TraceParent returns the span context formatted as W3C traceparent header value.
+func (sc SpanContext) TraceParent() string {
+	return fmt.Sprintf("00-%x-%x-%02x", sc.TraceID, sc.SpanID, sc.Flags)
+}
+
+// ParseTraceParent parses a W3C traceparent header value.
+// It returns an error wrapping ErrInvalidTraceParent if the value is malformed.
+func ParseTraceParent(traceParent string) (SpanContext, error) {
+	var sc SpanContext
+
+	parts := strings.Split(traceParent, "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return sc, fmt.Errorf("%w: %q", ErrInvalidTraceParent, traceParent)
+	}
+
+	traceID, traceErr := hex.DecodeString(parts[1])
+	spanID, spanErr := hex.DecodeString(parts[2])
+	flags, flagsErr := strconv.ParseUint(parts[3], 16, 8)
+
+	if traceErr != nil || spanErr != nil || flagsErr != nil ||
+		len(traceID) != len(sc.TraceID) || len(spanID) != len(sc.SpanID) || len(parts[3]) != 2 {
+		return sc, fmt.Errorf("%w: %q", ErrInvalidTraceParent, traceParent)
+	}
+
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+	sc.Flags = byte(flags)
+
+	if !sc.IsValid() {
+		return SpanContext{}, fmt.Errorf("%w: %q", ErrInvalidTraceParent, traceParent)
+	}
+
+	return sc, nil
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpanContext returns a copy of the context holding the span context.
+// Events published with the context become part of the trace.
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+// SpanContextFromContext returns the span context held by the context.
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(spanContextKey{}).(SpanContext)
+
+	return sc, ok && sc.IsValid()
+}
+
+// SpanContext returns the span context of the event parsed from its traceparent header.
+func (e *Event) SpanContext() (SpanContext, bool) {
+	sc, err := ParseTraceParent(e.Header(TraceParentHeader))
+
+	return sc, err == nil
+}
+
+// Span is a single operation within a trace.
+type Span interface {
+	SpanContext() SpanContext
+	SetAttribute(key, value string)
+	End(err error)
+}
+
+// Tracer creates spans around publishing and around every handler invocation, see WithTracer.
+type Tracer interface {
+	// Start starts a span that is a child of parent, or the root of a new trace if parent is invalid.
+	Start(ctx context.Context, name string, parent SpanContext) (context.Context, Span)
+}
+
+// trace sets the traceparent header of a published event and starts the publish span.
+// The span continues the trace of an existing traceparent header or of the publishing context.
+// Without a Tracer the event keeps its traceparent header or continues the trace of the publishing context,
+// events published without a trace get no traceparent header. The returned Span is nil if the EventBus has no Tracer.
+func (eb *EventBus) trace(evt Event) (Event, Span) {
+	parent, traced := evt.SpanContext()
+	if traced && eb.tracer == nil {
+		return evt, nil
+	}
+
+	if !traced {
+		parent, _ = SpanContextFromContext(evt.Context())
+	}
+
+	var (
+		sc   SpanContext
+		span Span
+	)
+
+	switch {
+	case eb.tracer != nil:
+		var ctx context.Context
+
+		ctx, span = eb.tracer.Start(evt.Context(), "publish "+evt.Topic, parent)
+		sc = span.SpanContext()
+		evt.ctx = ContextWithSpanContext(ctx, sc)
+
+		span.SetAttribute("topic", evt.Topic)
+		span.SetAttribute("event.id", evt.ID)
+	case parent.IsValid():
+		sc = parent
+	default:
+		return evt, nil
+	}
+
+	headers := make(map[string]string, len(evt.Headers)+1)
+	for key, value := range evt.Headers {
+		headers[key] = value
+	}
+
+	headers[TraceParentHeader] = sc.TraceParent()
+	evt.Headers = headers
+
+	return evt, span
+}
+
+// traceHandler starts the span around a handler invocation, using the event span as parent.
+// The returned Span is nil if the EventBus has no Tracer.
+func (eb *EventBus) traceHandler(sub *Subscription, evt Event) (Event, Span) {
+	if eb.tracer == nil {
+		return evt, nil
+	}
+
+	parent, _ := evt.SpanContext()
+
+	ctx, span := eb.tracer.Start(evt.Context(), "handle "+evt.Topic, parent)
+	evt.ctx = ContextWithSpanContext(ctx, span.SpanContext())
+
+	span.SetAttribute("topic", evt.Topic)
+	span.SetAttribute("event.id", evt.ID)
+	span.SetAttribute("subscription.id", strconv.FormatUint(sub.id, 10))
+	span.SetAttribute("attempt", strconv.Itoa(evt.Attempt()))
+
+	return evt, span
+}
+
+// endSpan ends the span if it is not nil.
+func endSpan(span Span, err error) {
+	if span != nil {
+		span.End(err)
+	}
+}
+
+// newRootSpanContext returns a span context starting a new trace.
+func newRootSpanContext() SpanContext {
+	var sc SpanContext
+
+	// crypto/rand does not fail on supported platforms.
+	_, _ = rand.Read(sc.TraceID[:])
+	_, _ = rand.Read(sc.SpanID[:])
+
+	return sc
+}
+
+// newSpanID returns a random span ID.
+func newSpanID() [8]byte {
+	var id [8]byte
+
+	_, _ = rand.Read(id[:])
+
+	return id
+}
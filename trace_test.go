@@ -0,0 +1,111 @@
+package eventbus_test
+
+import (
+	"context"
+	eb "github.com/dtomasi/go-event-bus/v3"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	sc, err := eb.ParseTraceParent(traceParent)
+	assert.NoError(t, err)
+	assert.True(t, sc.IsValid())
+	assert.Equal(t, eb.TraceFlagsSampled, sc.Flags)
+	assert.Equal(t, traceParent, sc.TraceParent())
+
+	for _, invalid := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e47-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-zz",
+	} {
+		_, err := eb.ParseTraceParent(invalid)
+		assert.ErrorIs(t, err, eb.ErrInvalidTraceParent, invalid)
+	}
+}
+
+func TestEventBus_TraceContext(t *testing.T) {
+	ebi := eb.NewEventBus()
+
+	sub, err := ebi.Subscribe("orders", eb.WithBufferSize(2))
+	assert.NoError(t, err)
+
+	parent, err := eb.ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.NoError(t, err)
+
+	// Events continue the trace of the publishing context
+	assert.NoError(t, ebi.PublishAsyncCtx(eb.ContextWithSpanContext(context.Background(), parent), "orders", "a"))
+
+	evt := <-sub.Channel()
+	sc, ok := evt.SpanContext()
+	assert.True(t, ok)
+	assert.Equal(t, parent, sc)
+
+	// Without a trace in the context and without a Tracer no trace is started
+	assert.NoError(t, ebi.PublishAsync("orders", "b"))
+
+	evt = <-sub.Channel()
+	_, ok = evt.SpanContext()
+	assert.False(t, ok)
+	assert.Nil(t, evt.Headers)
+}
+
+func TestEventBus_Tracer(t *testing.T) {
+	exporter := eb.NewInMemoryExporter()
+	ebi := eb.NewEventBus(eb.WithTracer(eb.NewTracer(exporter)))
+
+	var handled eb.SpanContext
+
+	sub, err := ebi.SubscribeHandler("orders", func(topic string, data interface{}) error {
+		return errTest
+	})
+	assert.NoError(t, err)
+
+	_, err = ebi.SubscribeEvent("orders", func(evt eb.Event) error {
+		handled, _ = eb.SpanContextFromContext(evt.Context())
+
+		return nil
+	})
+	assert.NoError(t, err)
+
+	parent, err := eb.ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.NoError(t, err)
+
+	ctx := eb.ContextWithSpanContext(context.Background(), parent)
+	assert.ErrorIs(t, ebi.PublishECtx(ctx, "orders", "order", eb.WithEventID("order-1")), errTest)
+
+	spans := exporter.Spans()
+	assert.Len(t, spans, 3)
+
+	// The publish span ends after all handlers acknowledged the event, handler errors are recorded by their spans
+	publish := spans[2]
+	assert.Equal(t, "publish orders", publish.Name)
+	assert.Equal(t, parent, publish.Parent)
+	assert.Equal(t, parent.TraceID, publish.SpanContext.TraceID)
+	assert.Equal(t, "order-1", publish.Attributes["event.id"])
+	assert.NoError(t, publish.Err)
+
+	for _, span := range spans[:2] {
+		assert.Equal(t, "handle orders", span.Name)
+		assert.Equal(t, publish.SpanContext, span.Parent)
+		assert.Equal(t, parent.TraceID, span.SpanContext.TraceID)
+		assert.Equal(t, "1", span.Attributes["attempt"])
+
+		if span.Attributes["subscription.id"] == strconv.FormatUint(sub.ID(), 10) {
+			assert.ErrorIs(t, span.Err, errTest)
+		} else {
+			assert.NoError(t, span.Err)
+			assert.Equal(t, span.SpanContext, handled)
+		}
+	}
+
+	exporter.Reset()
+	assert.Empty(t, exporter.Spans())
+}
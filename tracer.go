@@ -0,0 +1,135 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SpanData is a finished span recorded by the Tracer returned by NewTracer.
+type SpanData struct {
+	Name        string
+	SpanContext SpanContext
+	Parent      SpanContext
+	Attributes  map[string]string
+	Start       time.Time
+	End         time.Time
+	Err         error
+}
+
+// SpanExporter receives finished spans.
+type SpanExporter interface {
+	Export(span SpanData)
+}
+
+// NewTracer returns a Tracer passing finished spans to the exporter.
+// New traces are always sampled.
+func NewTracer(exporter SpanExporter) Tracer {
+	return &recordingTracer{exporter: exporter}
+}
+
+type recordingTracer struct {
+	exporter SpanExporter
+}
+
+// Start starts a new span.
+func (t *recordingTracer) Start(ctx context.Context, name string, parent SpanContext) (context.Context, Span) {
+	sc := SpanContext{
+		TraceID: parent.TraceID,
+		SpanID:  newSpanID(),
+		Flags:   parent.Flags,
+	}
+
+	if !parent.IsValid() {
+		sc = newRootSpanContext()
+		sc.Flags = TraceFlagsSampled
+	}
+
+	span := &recordingSpan{ //nolint:exhaustivestruct
+		exporter: t.exporter,
+		data: SpanData{ //nolint:exhaustivestruct
+			Name:        name,
+			SpanContext: sc,
+			Parent:      parent,
+			Attributes:  map[string]string{},
+			Start:       time.Now(),
+		},
+	}
+
+	return ContextWithSpanContext(ctx, sc), span
+}
+
+type recordingSpan struct {
+	exporter SpanExporter
+
+	mu    sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+// SpanContext returns the span context of the span.
+func (s *recordingSpan) SpanContext() SpanContext {
+	return s.data.SpanContext
+}
+
+// SetAttribute sets an attribute of the span.
+func (s *recordingSpan) SetAttribute(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.Attributes[key] = value
+}
+
+// End finishes the span and exports it. Ending a span more than once is a no-op.
+func (s *recordingSpan) End(err error) {
+	s.mu.Lock()
+
+	if s.ended {
+		s.mu.Unlock()
+
+		return
+	}
+
+	s.ended = true
+	s.data.End = time.Now()
+	s.data.Err = err
+	data := s.data
+	s.mu.Unlock()
+
+	s.exporter.Export(data)
+}
+
+// InMemoryExporter keeps finished spans in memory, mainly for tests.
+type InMemoryExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+// NewInMemoryExporter returns a new InMemoryExporter.
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{} //nolint:exhaustivestruct
+}
+
+// Export stores the span.
+func (e *InMemoryExporter) Export(span SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = append(e.spans, span)
+}
+
+// Spans returns all exported spans in the order they ended.
+func (e *InMemoryExporter) Spans() []SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]SpanData{}, e.spans...)
+}
+
+// Reset removes all exported spans.
+func (e *InMemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = nil
+}
@@ -0,0 +1,56 @@
+package wal
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+)
+
+// Codec encodes and decodes the data of events.
+type Codec interface {
+	Marshal(topic string, data interface{}) ([]byte, error)
+	Unmarshal(topic string, b []byte) (interface{}, error)
+}
+
+// GobCodec encodes data using encoding/gob.
+// Custom types must be registered using gob.Register to be decoded into their original type.
+type GobCodec struct{}
+
+// Marshal encodes the data.
+func (GobCodec) Marshal(_ string, data interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&data); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes the data.
+func (GobCodec) Unmarshal(_ string, b []byte) (interface{}, error) {
+	var data interface{}
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// JSONCodec encodes data using encoding/json.
+// Data is decoded into generic values like map[string]interface{} and float64.
+type JSONCodec struct{}
+
+// Marshal encodes the data.
+func (JSONCodec) Marshal(_ string, data interface{}) ([]byte, error) {
+	return json.Marshal(data)
+}
+
+// Unmarshal decodes the data.
+func (JSONCodec) Unmarshal(_ string, b []byte) (interface{}, error) {
+	var data interface{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
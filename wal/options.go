@@ -0,0 +1,68 @@
+package wal
+
+import (
+	"time"
+)
+
+// DefaultSegmentSize is the size in bytes after which a new segment file is started.
+const DefaultSegmentSize = 64 << 20
+
+// SyncPolicy defines when appended records are flushed to stable storage.
+type SyncPolicy int
+
+const (
+	// SyncAlways flushes every record before Append returns.
+	SyncAlways SyncPolicy = iota
+	// SyncInterval flushes records periodically, see WithSyncInterval.
+	SyncInterval
+	// SyncNever leaves flushing to the operating system.
+	SyncNever
+)
+
+// Option configures a Log.
+type Option func(l *Log)
+
+// WithCodec sets the Codec used to encode the data of events.
+// By default GobCodec is used.
+func WithCodec(codec Codec) Option {
+	return func(l *Log) {
+		l.codec = codec
+	}
+}
+
+// WithSegmentSize sets the size in bytes after which a new segment file is started.
+func WithSegmentSize(size int64) Option {
+	return func(l *Log) {
+		if size > 0 {
+			l.segmentSize = size
+		}
+	}
+}
+
+// WithSyncPolicy sets when appended records are flushed to stable storage.
+// By default every record is flushed using SyncAlways.
+func WithSyncPolicy(policy SyncPolicy) Option {
+	return func(l *Log) {
+		l.syncPolicy = policy
+	}
+}
+
+// WithSyncInterval flushes appended records periodically using SyncInterval.
+func WithSyncInterval(interval time.Duration) Option {
+	return func(l *Log) {
+		if interval > 0 {
+			l.syncPolicy = SyncInterval
+			l.syncInterval = interval
+		}
+	}
+}
+
+// WithRetention removes the oldest segments once all segments exceed maxBytes in total
+// or once they were last written more than maxAge ago. Zero means no limit.
+// The segment currently written to is never removed.
+func WithRetention(maxBytes int64, maxAge time.Duration) Option {
+	return func(l *Log) {
+		l.maxBytes = maxBytes
+		l.maxAge = maxAge
+	}
+}
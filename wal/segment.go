@@ -0,0 +1,269 @@
+package wal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	eb "github.com/dtomasi/go-event-bus/v3"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	segmentExt = ".wal"
+
+	// headerSize is the size of the record header holding the payload length and checksum.
+	headerSize = 8
+	// maxPayloadSize protects against allocating huge buffers for corrupt length fields.
+	maxPayloadSize = 1 << 30
+	// recordVersion is the version of the payload format holding the topic, the metadata and the data.
+	recordVersion = 1
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli) //nolint:gochecknoglobals
+
+// segment is a single file of the log.
+type segment struct {
+	index uint64
+	path  string
+	size  int64
+}
+
+// segmentPath returns the path of the segment file with the given index.
+func segmentPath(dir string, index uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%016d%s", index, segmentExt))
+}
+
+// listSegments returns all segment files in the directory ordered by index.
+func listSegments(dir string) ([]segment, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var segments []segment
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, segmentExt) {
+			continue
+		}
+
+		index, err := strconv.ParseUint(strings.TrimSuffix(name, segmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		segments = append(segments, segment{
+			index: index,
+			path:  filepath.Join(dir, name),
+			size:  info.Size(),
+		})
+	}
+
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].index < segments[j].index
+	})
+
+	return segments, nil
+}
+
+// record is the decoded payload of a log record.
+type record struct {
+	topic     string
+	id        string
+	source    string
+	timestamp time.Time
+	headers   map[string]string
+	data      []byte
+}
+
+// encodeRecord returns a record holding the topic, the metadata and the encoded data of an event.
+// A record consists of the payload length, a CRC-32C checksum of the payload and the payload.
+func encodeRecord(evt eb.Event, data []byte) []byte {
+	payload := []byte{recordVersion}
+	payload = appendString(payload, evt.Topic)
+	payload = appendString(payload, evt.ID)
+	payload = appendString(payload, evt.Source)
+
+	var timestamp int64
+	if !evt.Timestamp.IsZero() {
+		timestamp = evt.Timestamp.UnixNano()
+	}
+
+	payload = appendUvarint(payload, uint64(timestamp))
+	payload = appendUvarint(payload, uint64(len(evt.Headers)))
+
+	for key, value := range evt.Headers {
+		payload = appendString(payload, key)
+		payload = appendString(payload, value)
+	}
+
+	payload = append(payload, data...)
+
+	rec := make([]byte, headerSize, headerSize+len(payload))
+	binary.BigEndian.PutUint32(rec[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(rec[4:8], crc32.Checksum(payload, crcTable))
+
+	return append(rec, payload...)
+}
+
+// decodePayload decodes a record payload.
+func decodePayload(payload []byte) (record, error) {
+	var rec record
+
+	if len(payload) == 0 || payload[0] != recordVersion {
+		return rec, ErrCorrupt
+	}
+
+	d := decoder{buf: payload[1:]}
+	rec.topic = d.string()
+	rec.id = d.string()
+	rec.source = d.string()
+
+	if timestamp := int64(d.uvarint()); timestamp != 0 {
+		rec.timestamp = time.Unix(0, timestamp)
+	}
+
+	if count := d.uvarint(); count > 0 && count <= uint64(len(d.buf)) {
+		rec.headers = make(map[string]string, count)
+
+		for i := uint64(0); i < count && d.err == nil; i++ {
+			key := d.string()
+			rec.headers[key] = d.string()
+		}
+	} else if count > 0 {
+		d.err = ErrCorrupt
+	}
+
+	rec.data = d.buf
+
+	return rec, d.err
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+func appendString(b []byte, s string) []byte {
+	return append(appendUvarint(b, uint64(len(s))), s...)
+}
+
+// decoder reads the fields of a record payload and remembers the first error.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.err = ErrCorrupt
+
+		return 0
+	}
+
+	d.buf = d.buf[n:]
+
+	return v
+}
+
+func (d *decoder) string() string {
+	length := d.uvarint()
+	if d.err != nil {
+		return ""
+	}
+
+	if uint64(len(d.buf)) < length {
+		d.err = ErrCorrupt
+
+		return ""
+	}
+
+	s := string(d.buf[:length])
+	d.buf = d.buf[length:]
+
+	return s
+}
+
+// readRecord reads the payload of the next record.
+// It returns io.EOF at the end of the segment and ErrCorrupt for torn or damaged records.
+func readRecord(r io.Reader) ([]byte, error) {
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, ErrCorrupt
+		}
+
+		return nil, err
+	}
+
+	length := binary.BigEndian.Uint32(header[0:4])
+	if length > maxPayloadSize {
+		return nil, ErrCorrupt
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, ErrCorrupt
+		}
+
+		return nil, err
+	}
+
+	if crc32.Checksum(payload, crcTable) != binary.BigEndian.Uint32(header[4:8]) {
+		return nil, ErrCorrupt
+	}
+
+	return payload, nil
+}
+
+// scanSegment calls fn for the payload of every record within the first size bytes of the segment.
+// It returns the number of bytes holding valid records, which is less than size if a record is corrupt.
+func scanSegment(seg segment, fn func(payload []byte) error) (int64, error) {
+	f, err := os.Open(seg.path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(io.LimitReader(f, seg.size))
+
+	var valid int64
+
+	for {
+		payload, err := readRecord(r)
+		if errors.Is(err, io.EOF) {
+			return valid, nil
+		}
+
+		if err != nil {
+			return valid, fmt.Errorf("segment %s at offset %d: %w", seg.path, valid, err)
+		}
+
+		if err := fn(payload); err != nil {
+			return valid, err
+		}
+
+		valid += int64(headerSize + len(payload))
+	}
+}
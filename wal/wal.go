@@ -0,0 +1,344 @@
+// Package wal provides a file based write-ahead log that persists the events of an EventBus.
+//
+// The log is split into segment files. Every record is protected by a checksum,
+// records torn by a crash are removed when the log is opened again.
+package wal
+
+import (
+	"errors"
+	"fmt"
+	eb "github.com/dtomasi/go-event-bus/v3"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrClosed is returned when using a Log that has been closed.
+	ErrClosed = errors.New("log is closed")
+	// ErrCorrupt is returned when replaying a damaged record.
+	ErrCorrupt = errors.New("corrupt log record")
+)
+
+var _ eb.EventLog = (*Log)(nil)
+
+// Log is a file based write-ahead log implementing eventbus.EventLog.
+type Log struct {
+	dir          string
+	codec        Codec
+	segmentSize  int64
+	syncPolicy   SyncPolicy
+	syncInterval time.Duration
+	maxBytes     int64
+	maxAge       time.Duration
+
+	mu       sync.Mutex
+	segments []segment
+	active   *os.File
+	dirty    bool
+	closed   bool
+	err      error
+	stop     chan struct{}
+}
+
+// Open opens the log in the given directory, creating the directory if needed.
+// A torn record at the end of the last segment is removed.
+func Open(dir string, opts ...Option) (*Log, error) {
+	l := &Log{ //nolint:exhaustivestruct
+		dir:         dir,
+		codec:       GobCodec{},
+		segmentSize: DefaultSegmentSize,
+		syncPolicy:  SyncAlways,
+		stop:        make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil { //nolint:gomnd
+		return nil, err
+	}
+
+	segments, err := listSegments(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(segments) == 0 {
+		segments = []segment{{index: 1, path: segmentPath(dir, 1), size: 0}}
+	} else if err := recoverSegment(&segments[len(segments)-1]); err != nil {
+		return nil, err
+	}
+
+	l.segments = segments
+
+	if l.active, err = openSegment(segments[len(segments)-1].path); err != nil {
+		return nil, err
+	}
+
+	if err := l.enforceRetention(); err != nil {
+		_ = l.active.Close()
+
+		return nil, err
+	}
+
+	if l.syncPolicy == SyncInterval && l.syncInterval > 0 {
+		go l.syncPeriodically()
+	}
+
+	return l, nil
+}
+
+// openSegment opens a segment file for appending, creating it if needed.
+func openSegment(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644) //nolint:gomnd
+}
+
+// recoverSegment truncates the segment after the last valid record.
+func recoverSegment(seg *segment) error {
+	valid, err := scanSegment(*seg, func([]byte) error {
+		return nil
+	})
+	if err != nil && !errors.Is(err, ErrCorrupt) {
+		return err
+	}
+
+	if valid == seg.size {
+		return nil
+	}
+
+	if err := os.Truncate(seg.path, valid); err != nil {
+		return err
+	}
+
+	seg.size = valid
+
+	return nil
+}
+
+// Append writes the topic, the metadata and the data of the event to the log.
+// If a torn record can not be removed after a failed write, all further appends fail.
+func (l *Log) Append(evt eb.Event) error {
+	data, err := l.codec.Marshal(evt.Topic, evt.Data)
+	if err != nil {
+		return fmt.Errorf("encode data of topic %q: %w", evt.Topic, err)
+	}
+
+	rec := encodeRecord(evt, data)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return ErrClosed
+	}
+
+	if l.err != nil {
+		return l.err
+	}
+
+	seg := &l.segments[len(l.segments)-1]
+	if seg.size > 0 && seg.size+int64(len(rec)) > l.segmentSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+
+		seg = &l.segments[len(l.segments)-1]
+	}
+
+	if n, err := l.active.Write(rec); err != nil {
+		// A torn record is removed, otherwise records appended later on could not be replayed.
+		if n > 0 {
+			if err := l.active.Truncate(seg.size); err != nil {
+				l.err = fmt.Errorf("remove torn record: %w", err)
+			}
+		}
+
+		return err
+	}
+
+	seg.size += int64(len(rec))
+
+	if l.syncPolicy == SyncAlways {
+		return l.active.Sync()
+	}
+
+	l.dirty = true
+
+	return nil
+}
+
+// rotate closes the active segment and starts a new one. The caller must hold the lock.
+func (l *Log) rotate() error {
+	if err := l.active.Sync(); err != nil {
+		return err
+	}
+
+	if err := l.active.Close(); err != nil {
+		return err
+	}
+
+	index := l.segments[len(l.segments)-1].index + 1
+	path := segmentPath(l.dir, index)
+
+	active, err := openSegment(path)
+	if err != nil {
+		return err
+	}
+
+	l.active = active
+	l.dirty = false
+	l.segments = append(l.segments, segment{index: index, path: path, size: 0})
+
+	return l.enforceRetention()
+}
+
+// enforceRetention removes the oldest segments exceeding the retention limits.
+// The caller must hold the lock.
+func (l *Log) enforceRetention() error {
+	if l.maxBytes <= 0 && l.maxAge <= 0 {
+		return nil
+	}
+
+	var total int64
+	for _, seg := range l.segments {
+		total += seg.size
+	}
+
+	for len(l.segments) > 1 {
+		seg := l.segments[0]
+
+		expired := false
+
+		if l.maxAge > 0 {
+			info, err := os.Stat(seg.path)
+			if err != nil {
+				return err
+			}
+
+			expired = time.Since(info.ModTime()) > l.maxAge
+		}
+
+		if !expired && (l.maxBytes <= 0 || total <= l.maxBytes) {
+			return nil
+		}
+
+		if err := os.Remove(seg.path); err != nil {
+			return err
+		}
+
+		total -= seg.size
+		l.segments = l.segments[1:]
+	}
+
+	return nil
+}
+
+// Replay calls fn for every event in the log in the order they were appended.
+// Events appended while replaying are not included. Replay stops at the first error.
+func (l *Log) Replay(fn func(evt eb.Event) error) error {
+	l.mu.Lock()
+
+	if l.closed {
+		l.mu.Unlock()
+
+		return ErrClosed
+	}
+
+	segments := make([]segment, len(l.segments))
+	copy(segments, l.segments)
+
+	l.mu.Unlock()
+
+	for _, seg := range segments {
+		_, err := scanSegment(seg, func(payload []byte) error {
+			rec, err := decodePayload(payload)
+			if err != nil {
+				return fmt.Errorf("segment %s: %w", seg.path, err)
+			}
+
+			data, err := l.codec.Unmarshal(rec.topic, rec.data)
+			if err != nil {
+				return fmt.Errorf("decode data of topic %q: %w", rec.topic, err)
+			}
+
+			return fn(eb.Event{ //nolint:exhaustivestruct
+				Data:      data,
+				Topic:     rec.topic,
+				ID:        rec.id,
+				Timestamp: rec.timestamp,
+				Source:    rec.source,
+				Headers:   rec.headers,
+			})
+		})
+
+		// Segments removed by retention in the meantime are skipped.
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Sync flushes all appended records to stable storage.
+func (l *Log) Sync() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return ErrClosed
+	}
+
+	l.dirty = false
+
+	return l.active.Sync()
+}
+
+// syncPeriodically flushes appended records until the log is closed.
+func (l *Log) syncPeriodically() {
+	ticker := time.NewTicker(l.syncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			l.mu.Lock()
+
+			if l.dirty && !l.closed {
+				l.dirty = false
+				_ = l.active.Sync()
+			}
+
+			l.mu.Unlock()
+		case <-l.stop:
+			return
+		}
+	}
+}
+
+// Close flushes and closes the log. Calling Close more than once returns ErrClosed.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return ErrClosed
+	}
+
+	l.closed = true
+	close(l.stop)
+
+	if err := l.active.Sync(); err != nil {
+		_ = l.active.Close()
+
+		return err
+	}
+
+	return l.active.Close()
+}
@@ -0,0 +1,66 @@
+//go:build linux
+
+package wal_test
+
+import (
+	eb "github.com/dtomasi/go-event-bus/v3"
+	"github.com/dtomasi/go-event-bus/v3/wal"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+// limitFileSize limits the size of files written by the process until the returned function is called.
+func limitFileSize(t *testing.T, size uint64) func() {
+	t.Helper()
+
+	var limit syscall.Rlimit
+
+	assert.NoError(t, syscall.Getrlimit(syscall.RLIMIT_FSIZE, &limit))
+
+	// Writes exceeding the limit fail with EFBIG instead of terminating the process.
+	signal.Ignore(syscall.SIGXFSZ)
+
+	assert.NoError(t, syscall.Setrlimit(syscall.RLIMIT_FSIZE, &syscall.Rlimit{Cur: size, Max: limit.Max}))
+
+	return func() {
+		assert.NoError(t, syscall.Setrlimit(syscall.RLIMIT_FSIZE, &limit))
+		signal.Reset(syscall.SIGXFSZ)
+	}
+}
+
+func TestLog_AppendShortWrite(t *testing.T) {
+	dir := t.TempDir()
+
+	l, err := wal.Open(dir)
+	assert.NoError(t, err)
+
+	assert.NoError(t, l.Append(eb.Event{Topic: "orders", Data: "1"}))
+
+	info, err := os.Stat(segmentFiles(t, dir)[0])
+	assert.NoError(t, err)
+
+	// The record is only partially written
+	restore := limitFileSize(t, uint64(info.Size())+10)
+	err = l.Append(eb.Event{Topic: "orders", Data: strings.Repeat("2", 100)})
+	restore()
+
+	assert.Error(t, err)
+
+	assert.NoError(t, l.Append(eb.Event{Topic: "orders", Data: "3"}))
+
+	events := replayAll(t, l)
+	assert.Len(t, events, 2)
+	assert.Equal(t, "1", events[0].Data)
+	assert.Equal(t, "3", events[1].Data)
+	assert.NoError(t, l.Close())
+
+	// Reopening the log keeps the records appended after the failed write
+	l, err = wal.Open(dir)
+	assert.NoError(t, err)
+	assert.Len(t, replayAll(t, l), 2)
+	assert.NoError(t, l.Close())
+}
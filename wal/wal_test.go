@@ -0,0 +1,237 @@
+package wal_test
+
+import (
+	"context"
+	"encoding/gob"
+	eb "github.com/dtomasi/go-event-bus/v3"
+	"github.com/dtomasi/go-event-bus/v3/wal"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type orderCreated struct {
+	ID int
+}
+
+//nolint:gochecknoinits
+func init() {
+	gob.Register(orderCreated{})
+}
+
+func replayAll(t *testing.T, l *wal.Log) []eb.Event {
+	t.Helper()
+
+	var events []eb.Event
+
+	assert.NoError(t, l.Replay(func(evt eb.Event) error {
+		events = append(events, evt)
+
+		return nil
+	}))
+
+	return events
+}
+
+func segmentFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.wal"))
+	assert.NoError(t, err)
+
+	return files
+}
+
+func TestLog_AppendReplay(t *testing.T) {
+	dir := t.TempDir()
+
+	l, err := wal.Open(dir)
+	assert.NoError(t, err)
+
+	timestamp := time.Now()
+
+	assert.NoError(t, l.Append(eb.Event{ //nolint:exhaustivestruct
+		Topic:     "orders:created",
+		Data:      orderCreated{ID: 1},
+		ID:        "order-1",
+		Timestamp: timestamp,
+		Source:    "shop",
+		Headers:   map[string]string{"tenant": "acme"},
+	}))
+	assert.NoError(t, l.Append(eb.Event{Topic: "orders:deleted", Data: "2"}))
+	assert.NoError(t, l.Close())
+	assert.ErrorIs(t, l.Close(), wal.ErrClosed)
+	assert.ErrorIs(t, l.Append(eb.Event{Topic: "orders:created"}), wal.ErrClosed)
+
+	// Reopening the log keeps all events and appends after them
+	l, err = wal.Open(dir)
+	assert.NoError(t, err)
+
+	assert.NoError(t, l.Append(eb.Event{Topic: "orders:created", Data: orderCreated{ID: 3}}))
+
+	events := replayAll(t, l)
+	assert.Len(t, events, 3)
+	assert.Equal(t, "orders:created", events[0].Topic)
+	assert.Equal(t, orderCreated{ID: 1}, events[0].Data)
+	assert.Equal(t, "order-1", events[0].ID)
+	assert.True(t, timestamp.Equal(events[0].Timestamp))
+	assert.Equal(t, "shop", events[0].Source)
+	assert.Equal(t, map[string]string{"tenant": "acme"}, events[0].Headers)
+	assert.True(t, events[1].Timestamp.IsZero())
+	assert.Nil(t, events[1].Headers)
+	assert.Equal(t, "2", events[1].Data)
+	assert.Equal(t, orderCreated{ID: 3}, events[2].Data)
+
+	assert.NoError(t, l.Close())
+}
+
+func TestLog_Segments(t *testing.T) {
+	dir := t.TempDir()
+
+	l, err := wal.Open(dir, wal.WithSegmentSize(64), wal.WithSyncPolicy(wal.SyncNever))
+	assert.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, l.Append(eb.Event{Topic: "orders", Data: i}))
+	}
+
+	assert.Greater(t, len(segmentFiles(t, dir)), 1)
+
+	events := replayAll(t, l)
+	assert.Len(t, events, 10)
+
+	for i, evt := range events {
+		assert.Equal(t, i, evt.Data)
+	}
+
+	assert.NoError(t, l.Close())
+}
+
+func TestLog_Retention(t *testing.T) {
+	dir := t.TempDir()
+
+	l, err := wal.Open(dir, wal.WithSegmentSize(64), wal.WithRetention(128, 0))
+	assert.NoError(t, err)
+
+	for i := 0; i < 20; i++ {
+		assert.NoError(t, l.Append(eb.Event{Topic: "orders", Data: i}))
+	}
+
+	// Only the most recent events are kept
+	events := replayAll(t, l)
+	assert.NotEmpty(t, events)
+	assert.Less(t, len(events), 20)
+	assert.Equal(t, 19, events[len(events)-1].Data)
+
+	assert.NoError(t, l.Close())
+}
+
+func TestLog_TornRecord(t *testing.T) {
+	dir := t.TempDir()
+
+	l, err := wal.Open(dir)
+	assert.NoError(t, err)
+
+	assert.NoError(t, l.Append(eb.Event{Topic: "orders", Data: 1}))
+	assert.NoError(t, l.Append(eb.Event{Topic: "orders", Data: 2}))
+	assert.NoError(t, l.Close())
+
+	// Simulate a crash while writing the last record
+	files := segmentFiles(t, dir)
+	info, err := os.Stat(files[0])
+	assert.NoError(t, err)
+	assert.NoError(t, os.Truncate(files[0], info.Size()-3))
+
+	l, err = wal.Open(dir)
+	assert.NoError(t, err)
+
+	assert.NoError(t, l.Append(eb.Event{Topic: "orders", Data: 3}))
+
+	events := replayAll(t, l)
+	assert.Len(t, events, 2)
+	assert.Equal(t, 1, events[0].Data)
+	assert.Equal(t, 3, events[1].Data)
+
+	assert.NoError(t, l.Close())
+}
+
+func TestLog_Corrupt(t *testing.T) {
+	dir := t.TempDir()
+
+	l, err := wal.Open(dir, wal.WithSegmentSize(64))
+	assert.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, l.Append(eb.Event{Topic: "orders", Data: i}))
+	}
+
+	// Damage the payload of the first record in the oldest segment
+	files := segmentFiles(t, dir)
+	b, err := os.ReadFile(files[0])
+	assert.NoError(t, err)
+
+	b[len(b)-1] ^= 0xff
+	assert.NoError(t, os.WriteFile(files[0], b, 0o600))
+
+	err = l.Replay(func(evt eb.Event) error {
+		return nil
+	})
+	assert.ErrorIs(t, err, wal.ErrCorrupt)
+
+	assert.NoError(t, l.Close())
+}
+
+func TestLog_JSONCodec(t *testing.T) {
+	l, err := wal.Open(t.TempDir(), wal.WithCodec(wal.JSONCodec{}), wal.WithSyncInterval(10*time.Millisecond))
+	assert.NoError(t, err)
+
+	assert.NoError(t, l.Append(eb.Event{Topic: "orders", Data: orderCreated{ID: 1}}))
+	assert.NoError(t, l.Sync())
+
+	events := replayAll(t, l)
+	assert.Len(t, events, 1)
+	assert.Equal(t, map[string]interface{}{"ID": float64(1)}, events[0].Data)
+
+	assert.NoError(t, l.Close())
+}
+
+func TestLog_EventBus(t *testing.T) {
+	dir := t.TempDir()
+
+	l, err := wal.Open(dir)
+	assert.NoError(t, err)
+
+	ebi := eb.NewEventBus(eb.WithEventLog(l))
+
+	_, err = ebi.Publish("orders:created", orderCreated{ID: 1})
+	assert.NoError(t, err)
+	assert.NoError(t, ebi.PublishAsync("orders:created", orderCreated{ID: 2}))
+	assert.NoError(t, ebi.Close())
+	assert.NoError(t, l.Close())
+
+	// Restore the events into a fresh bus after a restart
+	l, err = wal.Open(dir)
+	assert.NoError(t, err)
+
+	ebi = eb.NewEventBus(eb.WithEventLog(l))
+
+	sub, err := ebi.Subscribe("orders:*", eb.WithBufferSize(2))
+	assert.NoError(t, err)
+
+	count, err := ebi.ReplayLog()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	_, err = ebi.Drain(context.Background())
+	assert.NoError(t, err)
+
+	assert.Equal(t, orderCreated{ID: 1}, (<-sub.Channel()).Data)
+	assert.Equal(t, orderCreated{ID: 2}, (<-sub.Channel()).Data)
+
+	// Replayed events are not logged again
+	assert.Len(t, replayAll(t, l), 2)
+
+	assert.NoError(t, l.Close())
+}